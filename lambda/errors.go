@@ -32,11 +32,11 @@ func lambdaErrorResponse(invokeError error) *messages.InvokeResponse_Error {
 	}
 }
 
-func lambdaPanicResponse(err interface{}) *messages.InvokeResponse_Error {
+func lambdaPanicResponse(err interface{}, stackDepth int) *messages.InvokeResponse_Error {
 	if ive, ok := err.(messages.InvokeResponse_Error); ok {
 		return &ive
 	}
-	panicInfo := getPanicInfo(err)
+	panicInfo := getPanicInfo(err, stackDepth)
 	return &messages.InvokeResponse_Error{
 		Message:    panicInfo.Message,
 		Type:       getErrorType(err),
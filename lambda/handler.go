@@ -15,6 +15,7 @@ import (
 	"sync"
 
 	"github.com/aws/aws-lambda-go/lambda/handlertrace"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 )
 
 type Handler interface {
@@ -33,6 +34,9 @@ type handlerOptions struct {
 	enableSIGTERM                    bool
 	sigtermCallbacks                 []func()
 	jsonOutBufferPool                *sync.Pool // contains *jsonOutBuffer
+	handlerWrappers                  []func(handlerFunc) handlerFunc
+	shutdownCallback                 func()
+	stackDepth                       int
 }
 
 type Option func(*handlerOptions)
@@ -91,6 +95,16 @@ func WithEnableSIGTERM(callbacks ...func()) Option {
 	})
 }
 
+// WithStackDepth sets the maximum number of frames of the stack trace reported when the handler panics.
+// The default is 32 frames. Values below 1 are ignored.
+func WithStackDepth(n int) Option {
+	return Option(func(h *handlerOptions) {
+		if n > 0 {
+			h.stackDepth = n
+		}
+	})
+}
+
 // handlerTakesContext returns whether the handler takes a context.Context as its first argument.
 func handlerTakesContext(handler reflect.Type) (bool, error) {
 	switch handler.NumIn() {
@@ -175,6 +189,7 @@ func newHandler(handlerFunc interface{}, options ...Option) *handlerOptions {
 		jsonResponseIndentPrefix: "",
 		jsonResponseIndentValue:  "",
 		jsonOutBufferPool:        pool,
+		stackDepth:               defaultErrorFrameCount,
 	}
 	for _, option := range options {
 		option(h)
@@ -182,10 +197,17 @@ func newHandler(handlerFunc interface{}, options ...Option) *handlerOptions {
 	for k, v := range h.contextValues {
 		h.baseContext = context.WithValue(h.baseContext, k, v)
 	}
+	if h.shutdownCallback != nil {
+		// run last, as the shutdown callback may exit the process
+		h.sigtermCallbacks = append(h.sigtermCallbacks, h.shutdownCallback)
+	}
 	if h.enableSIGTERM {
 		enableSIGTERM(h.sigtermCallbacks)
 	}
 	h.handlerFunc = reflectHandler(handlerFunc, h)
+	for _, wrap := range h.handlerWrappers {
+		h.handlerFunc = wrap(h.handlerFunc)
+	}
 	return h
 }
 
@@ -293,20 +315,24 @@ func reflectHandler(f interface{}, h *handlerOptions) handlerFunc {
 
 		// construct arguments
 		var args []reflect.Value
-		if takesContext {
-			args = append(args, reflect.ValueOf(ctx))
-		}
 		if (handlerType.NumIn() == 1 && !takesContext) || handlerType.NumIn() == 2 {
 			eventType := handlerType.In(handlerType.NumIn() - 1)
 			event := reflect.New(eventType)
 			if err := decoder.Decode(event.Interface()); err != nil {
 				return nil, err
 			}
+			ctx = lambdacontext.WithEventType(ctx, eventType.String())
+			if arn := eventSourceARN(event.Elem()); arn != "" {
+				ctx = lambdacontext.WithEventSourceARN(ctx, arn)
+			}
 			if nil != trace.RequestEvent {
 				trace.RequestEvent(ctx, event.Elem().Interface())
 			}
 			args = append(args, event.Elem())
 		}
+		if takesContext {
+			args = append([]reflect.Value{reflect.ValueOf(ctx)}, args...)
+		}
 
 		response := handler.Call(args)
 
@@ -349,3 +375,26 @@ func reflectHandler(f interface{}, h *handlerOptions) handlerFunc {
 		return out, nil
 	}
 }
+
+// eventSourceARN returns the event source ARN of event, read from an EventSourceARN field of the event
+// or of the first of its Records, as used by the SQS, Kinesis and DynamoDB Streams events.
+func eventSourceARN(event reflect.Value) string {
+	for event.Kind() == reflect.Ptr || event.Kind() == reflect.Interface {
+		if event.IsNil() {
+			return ""
+		}
+		event = event.Elem()
+	}
+	if event.Kind() != reflect.Struct {
+		return ""
+	}
+	for _, name := range []string{"EventSourceARN", "EventSourceArn"} {
+		if field := event.FieldByName(name); field.IsValid() && field.Kind() == reflect.String {
+			return field.String()
+		}
+	}
+	if records := event.FieldByName("Records"); records.IsValid() && records.Kind() == reflect.Slice && records.Len() > 0 {
+		return eventSourceARN(records.Index(0))
+	}
+	return ""
+}
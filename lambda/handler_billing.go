@@ -0,0 +1,45 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambda
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// WithBillingLog logs the estimated billed duration of each invocation to logger when the handler returns,
+// including when it returns an error or panics. The record includes billedDurationMs, the wall-clock duration
+// of the handler rounded up to the next millisecond, Lambda's billing granularity, and the requestId.
+// The estimate excludes the time spent by the runtime outside of the handler, such as decoding the invocation.
+func WithBillingLog(logger *slog.Logger) Option {
+	return Option(func(h *handlerOptions) {
+		h.handlerWrappers = append(h.handlerWrappers, func(next handlerFunc) handlerFunc {
+			return func(ctx context.Context, payload []byte) (io.Reader, error) {
+				start := time.Now()
+				defer func() {
+					var requestID string
+					if lc, ok := lambdacontext.FromContext(ctx); ok {
+						requestID = lc.AwsRequestID
+					}
+					// the requestId is added explicitly, so ctx is not passed to avoid duplicating it
+					logger.LogAttrs(context.Background(), slog.LevelInfo, "invocation billed duration",
+						slog.Int64("billedDurationMs", billedMillis(time.Since(start))),
+						slog.String("requestId", requestID))
+				}()
+				return next(ctx, payload)
+			}
+		})
+	})
+}
+
+// billedMillis returns d in milliseconds, rounded up.
+func billedMillis(d time.Duration) int64 {
+	return int64((d + time.Millisecond - 1) / time.Millisecond)
+}
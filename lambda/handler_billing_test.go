@@ -0,0 +1,66 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBillingLog(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler func() error
+		wantErr bool
+	}{
+		{name: "success", handler: func() error { time.Sleep(2 * time.Millisecond); return nil }},
+		{name: "error", handler: func() error { return errors.New("failed") }, wantErr: true},
+		{name: "panic", handler: func() error { panic("boom") }, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			handler := NewHandlerWithOptions(tc.handler, WithBillingLog(logger))
+			ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "test-request-id"})
+
+			err := func() (err error) {
+				defer func() {
+					if v := recover(); v != nil {
+						err = errors.New("panicked")
+					}
+				}()
+				_, err = handler.Invoke(ctx, []byte(`{}`))
+				return err
+			}()
+			assert.Equal(t, tc.wantErr, err != nil)
+
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+			assert.Equal(t, "test-request-id", logOutput["requestId"])
+			assert.Contains(t, logOutput, "billedDurationMs")
+			if tc.name == "success" {
+				assert.GreaterOrEqual(t, logOutput["billedDurationMs"], float64(2))
+			}
+		})
+	}
+}
+
+func TestBilledMillis(t *testing.T) {
+	assert.Equal(t, int64(0), billedMillis(0))
+	assert.Equal(t, int64(1), billedMillis(time.Microsecond))
+	assert.Equal(t, int64(1), billedMillis(time.Millisecond))
+	assert.Equal(t, int64(2), billedMillis(1001*time.Microsecond))
+}
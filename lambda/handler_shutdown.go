@@ -0,0 +1,47 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambda
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// processStart approximates the start of the process, for the uptime logged at shutdown.
+var processStart = time.Now()
+
+// exitProcess exits the process after the shutdown log, replaced in tests.
+var exitProcess = os.Exit
+
+// WithShutdownLog enables SIGTERM, as WithEnableSIGTERM does, and on SIGTERM logs an INFO record to logger with
+// the number of invocations handled as invocations and the uptime of the process in milliseconds as uptimeMs.
+// The handler of logger is then flushed and closed, see lambdacontext.Close, and the process exits with status 0.
+// The record is logged after the callbacks passed to WithEnableSIGTERM have run.
+func WithShutdownLog(logger *slog.Logger) Option {
+	return Option(func(h *handlerOptions) {
+		var invocations atomic.Int64
+		h.handlerWrappers = append(h.handlerWrappers, func(next handlerFunc) handlerFunc {
+			return func(ctx context.Context, payload []byte) (io.Reader, error) {
+				invocations.Add(1)
+				return next(ctx, payload)
+			}
+		})
+		h.shutdownCallback = func() {
+			logger.Info("shutting down",
+				slog.Int64("invocations", invocations.Load()),
+				slog.Int64("uptimeMs", time.Since(processStart).Milliseconds()))
+			_ = lambdacontext.Close(logger.Handler())
+			exitProcess(0)
+		}
+		h.enableSIGTERM = true
+	})
+}
@@ -0,0 +1,80 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"runtime"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func TestWithShutdownLog(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM cannot be sent on windows")
+	}
+	t.Setenv("AWS_LAMBDA_RUNTIME_API", "")
+
+	exited := make(chan int, 1)
+	exitProcess = func(code int) { exited <- code }
+	t.Cleanup(func() { exitProcess = os.Exit })
+
+	var buf syncBuffer
+	callbackRan := false
+	handler := newHandler(func() error { return nil },
+		WithShutdownLog(slog.New(slog.NewJSONHandler(&buf, nil))),
+		WithEnableSIGTERM(func() { callbackRan = true }),
+	)
+	for i := 0; i < 3; i++ {
+		_, err := handler.Invoke(context.Background(), []byte(`{}`))
+		require.NoError(t, err)
+	}
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(syscall.SIGTERM))
+	select {
+	case code := <-exited:
+		assert.Equal(t, 0, code)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the process did not exit after SIGTERM")
+	}
+
+	assert.True(t, callbackRan)
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "INFO", logOutput["level"])
+	assert.Equal(t, "shutting down", logOutput["msg"])
+	assert.Equal(t, float64(3), logOutput["invocations"])
+	assert.Contains(t, logOutput, "uptimeMs")
+}
@@ -0,0 +1,43 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambda
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// WithSlowInvocationLog logs a warning to logger for each invocation whose handler takes longer than threshold,
+// including when it returns an error or panics. The record includes durationMs, the wall-clock duration
+// of the handler in milliseconds, and the requestId. Nothing is logged for invocations within the threshold.
+func WithSlowInvocationLog(threshold time.Duration, logger *slog.Logger) Option {
+	return Option(func(h *handlerOptions) {
+		h.handlerWrappers = append(h.handlerWrappers, func(next handlerFunc) handlerFunc {
+			return func(ctx context.Context, payload []byte) (io.Reader, error) {
+				start := time.Now()
+				defer func() {
+					duration := time.Since(start)
+					if duration <= threshold {
+						return
+					}
+					var requestID string
+					if lc, ok := lambdacontext.FromContext(ctx); ok {
+						requestID = lc.AwsRequestID
+					}
+					// the requestId is added explicitly, so ctx is not passed to avoid duplicating it
+					logger.LogAttrs(context.Background(), slog.LevelWarn, "slow invocation",
+						slog.Float64("durationMs", float64(duration)/float64(time.Millisecond)),
+						slog.String("requestId", requestID))
+				}()
+				return next(ctx, payload)
+			}
+		})
+	})
+}
@@ -0,0 +1,56 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSlowInvocationLog(t *testing.T) {
+	testCases := []struct {
+		name      string
+		threshold time.Duration
+		handler   func() error
+		wantErr   bool
+		wantLog   bool
+	}{
+		{name: "above threshold", threshold: time.Millisecond, handler: func() error { time.Sleep(5 * time.Millisecond); return nil }, wantLog: true},
+		{name: "below threshold", threshold: time.Minute, handler: func() error { return nil }},
+		{name: "error above threshold", threshold: time.Millisecond, handler: func() error { time.Sleep(5 * time.Millisecond); return errors.New("failed") }, wantErr: true, wantLog: true},
+		{name: "error below threshold", threshold: time.Minute, handler: func() error { return errors.New("failed") }, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			handler := NewHandlerWithOptions(tc.handler, WithSlowInvocationLog(tc.threshold, logger))
+			ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "test-request-id"})
+
+			_, err := handler.Invoke(ctx, []byte(`{}`))
+			assert.Equal(t, tc.wantErr, err != nil)
+
+			if !tc.wantLog {
+				assert.Empty(t, buf.String())
+				return
+			}
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+			assert.Equal(t, "WARN", logOutput["level"])
+			assert.Equal(t, "test-request-id", logOutput["requestId"])
+			assert.GreaterOrEqual(t, logOutput["durationMs"], float64(5))
+		})
+	}
+}
@@ -14,8 +14,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda/handlertrace"
 	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -519,3 +521,35 @@ func TestHandlerTrace(t *testing.T) {
 		t.Error("response callbacks not called as expected", responseHistory)
 	}
 }
+
+func TestEventSourceARNContext(t *testing.T) {
+	handler := NewHandler(func(ctx context.Context, event events.SQSEvent) (string, error) {
+		arn, _ := lambdacontext.EventSourceARNFromContext(ctx)
+		return arn, nil
+	})
+	response, err := handler.Invoke(context.Background(), []byte(`{"Records": [{"eventSourceARN": "arn:aws:sqs:us-east-2:123456789012:my-queue"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"arn:aws:sqs:us-east-2:123456789012:my-queue"`, string(response))
+
+	response, err = handler.Invoke(context.Background(), []byte(`{"Records": []}`))
+	require.NoError(t, err)
+	assert.Equal(t, `""`, string(response))
+}
+
+func TestEventTypeContext(t *testing.T) {
+	handler := NewHandler(func(ctx context.Context, event events.SQSEvent) (string, error) {
+		name, _ := lambdacontext.EventTypeFromContext(ctx)
+		return name, nil
+	})
+	response, err := handler.Invoke(context.Background(), []byte(`{"Records": []}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"events.SQSEvent"`, string(response))
+
+	handler = NewHandler(func(ctx context.Context) (bool, error) {
+		_, ok := lambdacontext.EventTypeFromContext(ctx)
+		return ok, nil
+	})
+	response, err = handler.Invoke(context.Background(), []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, `false`, string(response))
+}
@@ -22,6 +22,10 @@ const (
 	nsPerMS = int64(time.Millisecond / time.Nanosecond)
 )
 
+// marshal encodes the error payloads that the runtime logs and reports to the Runtime API.
+// It may be replaced with a faster encoder compatible with json.Marshal, for example from a file behind a build tag.
+var marshal = json.Marshal
+
 // TODO: replace with time.UnixMillis after dropping version <1.17 from CI workflows
 func unixMS(ms int64) time.Time {
 	return time.Unix(ms/msPerS, (ms%msPerS)*nsPerMS)
@@ -63,6 +67,11 @@ func handleInvoke(invoke *invoke, handler *handlerOptions) error {
 	}
 	ctx = lambdacontext.NewContext(ctx, &lc)
 
+	// set the retry attempt, passed by callers that retry in the client context
+	if attempt, err := strconv.Atoi(lc.ClientContext.Custom["retryAttempt"]); err == nil {
+		ctx = lambdacontext.WithRetryAttempt(ctx, attempt)
+	}
+
 	// set the trace id
 	traceID := invoke.headers.Get(headerTraceID)
 	if lambdacontext.MaxConcurrency() == 1 {
@@ -72,7 +81,10 @@ func handleInvoke(invoke *invoke, handler *handlerOptions) error {
 	ctx = context.WithValue(ctx, "x-amzn-trace-id", traceID)
 
 	// call the handler, marshal any returned error
-	response, invokeErr := callBytesHandlerFunc(ctx, invoke.payload.Bytes(), handler.handlerFunc)
+	// the invocation ends once its response, which may be streamed, or its error has been sent
+	lambdacontext.InvokeStarted(ctx)
+	defer lambdacontext.InvokeEnded(ctx)
+	response, invokeErr := callBytesHandlerFunc(ctx, invoke.payload.Bytes(), handler.handlerFunc, handler.stackDepth)
 	if invokeErr != nil {
 		if err := reportFailure(invoke, invokeErr); err != nil {
 			return err
@@ -105,7 +117,7 @@ func reportFailure(invoke *invoke, invokeErr *messages.InvokeResponse_Error) err
 	errorPayload := safeMarshal(invokeErr)
 	log.Printf("%s", errorPayload)
 
-	causeForXRay, err := json.Marshal(makeXRayError(invokeErr))
+	causeForXRay, err := marshal(makeXRayError(invokeErr))
 	if err != nil {
 		return fmt.Errorf("unexpected error occured when serializing the function error cause for X-Ray: %v", err)
 	}
@@ -116,10 +128,10 @@ func reportFailure(invoke *invoke, invokeErr *messages.InvokeResponse_Error) err
 	return nil
 }
 
-func callBytesHandlerFunc(ctx context.Context, payload []byte, handler handlerFunc) (response io.Reader, invokeErr *messages.InvokeResponse_Error) {
+func callBytesHandlerFunc(ctx context.Context, payload []byte, handler handlerFunc, stackDepth int) (response io.Reader, invokeErr *messages.InvokeResponse_Error) {
 	defer func() {
 		if err := recover(); err != nil {
-			invokeErr = lambdaPanicResponse(err)
+			invokeErr = lambdaPanicResponse(err, stackDepth)
 		}
 	}()
 	response, err := handler(ctx, payload)
@@ -158,13 +170,13 @@ func parseClientContext(invoke *invoke, out *lambdacontext.ClientContext) error
 }
 
 func safeMarshal(v interface{}) []byte {
-	payload, err := json.Marshal(v)
+	payload, err := marshal(v)
 	if err != nil {
 		v := &messages.InvokeResponse_Error{
 			Type:    "Runtime.SerializationError",
 			Message: err.Error(),
 		}
-		payload, err := json.Marshal(v)
+		payload, err := marshal(v)
 		if err != nil {
 			panic(err) // never reach
 		}
@@ -277,6 +277,63 @@ func TestRuntimeAPIContextPlumbing(t *testing.T) {
 	assert.JSONEq(t, expected2, string(record.responses[1]))
 }
 
+// eofRecorder is a reader recording whether it was read to the end.
+type eofRecorder struct {
+	reader io.Reader
+	eof    bool
+}
+
+func (r *eofRecorder) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+func TestInvokeEndedAfterResponseSent(t *testing.T) {
+	response := &eofRecorder{reader: strings.NewReader("streamed")}
+	handler := NewHandler(func() (io.Reader, error) {
+		return response, nil
+	})
+	var sentBeforeEnd []bool
+	unregister := lambdacontext.OnInvokeEnd(func(context.Context) {
+		sentBeforeEnd = append(sentBeforeEnd, response.eof)
+	})
+	defer unregister()
+
+	ts, record := runtimeAPIServer(`{}`, 1)
+	defer ts.Close()
+	endpoint := strings.Split(ts.URL, "://")[1]
+	_ = startRuntimeAPILoop(endpoint, handler)
+
+	assert.Equal(t, "streamed", string(record.responses[0]))
+	assert.Equal(t, []bool{true}, sentBeforeEnd)
+}
+
+func TestRuntimeAPIRetryAttemptPlumbing(t *testing.T) {
+	handler := NewHandler(func(ctx context.Context) (interface{}, error) {
+		// the attempt is read without the client context, as it is set on the context by the invoke loop
+		attempt, ok := lambdacontext.RetryAttemptFromContext(lambdacontext.NewContext(ctx, &lambdacontext.LambdaContext{}))
+		return struct {
+			Attempt int
+			Present bool
+		}{attempt, ok}, nil
+	})
+
+	metadata2 := defaultInvokeMetadata()
+	metadata2.clientContext = `{"custom": {"retryAttempt": "2"}}`
+	ts, record := runtimeAPIServer(``, 2, defaultInvokeMetadata(), metadata2)
+	defer ts.Close()
+
+	endpoint := strings.Split(ts.URL, "://")[1]
+	expectedError := fmt.Sprintf("failed to GET http://%s/2018-06-01/runtime/invocation/next: got unexpected status code: 410", endpoint)
+	assert.EqualError(t, startRuntimeAPILoop(endpoint, handler), expectedError)
+
+	assert.JSONEq(t, `{"Attempt": 0, "Present": false}`, string(record.responses[0]))
+	assert.JSONEq(t, `{"Attempt": 2, "Present": true}`, string(record.responses[1]))
+}
+
 func TestReadPayload(t *testing.T) {
 	ts, record := runtimeAPIServer(`{"message": "I am craving tacos"}`, 1)
 	defer ts.Close()
@@ -395,6 +452,44 @@ func (invalidPayload) MarshalJSON() ([]byte, error) {
 	return nil, errors.New(`some error that contains '"'`)
 }
 
+func TestSafeMarshal_CustomMarshal(t *testing.T) {
+	original := marshal
+	defer func() { marshal = original }()
+
+	marshal = func(v interface{}) ([]byte, error) {
+		return []byte(`"custom"`), nil
+	}
+	assert.Equal(t, `"custom"`, string(safeMarshal(&messages.InvokeResponse_Error{Message: "hello"})))
+}
+
+func BenchmarkSafeMarshal(b *testing.B) {
+	original := marshal
+	defer func() { marshal = original }()
+
+	payload := lambdaErrorResponse(errors.New("something went wrong"))
+	encoders := map[string]func(interface{}) ([]byte, error){
+		"json.Marshal": json.Marshal,
+		"json.Encoder": func(v interface{}) ([]byte, error) {
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(v); err != nil {
+				return nil, err
+			}
+			return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+		},
+	}
+	for name, encoder := range encoders {
+		b.Run(name, func(b *testing.B) {
+			marshal = encoder
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				safeMarshal(payload)
+			}
+		})
+	}
+}
+
 func TestSafeMarshal_SerializationError(t *testing.T) {
 	payload := safeMarshal(invalidPayload{})
 	want := `{"errorMessage":"json: error calling MarshalJSON for type lambda.invalidPayload: some error that contains '\"'","errorType":"Runtime.SerializationError"}`
@@ -15,9 +15,9 @@ type panicInfo struct {
 	StackTrace []*messages.InvokeResponse_Error_StackFrame // Stack trace of the panic
 }
 
-func getPanicInfo(value interface{}) panicInfo {
+func getPanicInfo(value interface{}, stackDepth int) panicInfo {
 	message := getPanicMessage(value)
-	stack := getPanicStack()
+	stack := getPanicStack(stackDepth)
 
 	return panicInfo{Message: message, StackTrace: stack}
 }
@@ -28,8 +28,8 @@ func getPanicMessage(value interface{}) string {
 
 var defaultErrorFrameCount = 32
 
-func getPanicStack() []*messages.InvokeResponse_Error_StackFrame {
-	s := make([]uintptr, defaultErrorFrameCount)
+func getPanicStack(stackDepth int) []*messages.InvokeResponse_Error_StackFrame {
+	s := make([]uintptr, stackDepth)
 	const framesToHide = 3 // this (getPanicStack) -> getPanicInfo -> handler defer func
 	n := runtime.Callers(framesToHide, s)
 	if n == 0 {
@@ -1,6 +1,7 @@
 package lambda
 
 import (
+	"context"
 	"errors"
 	"os"
 	"runtime"
@@ -9,12 +10,13 @@ import (
 
 	"github.com/aws/aws-lambda-go/lambda/messages"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func assertPanicMessage(t *testing.T, panicFunc func(), expectedMessage string) {
 	defer func() {
 		if err := recover(); err != nil {
-			panicInfo := getPanicInfo(err)
+			panicInfo := getPanicInfo(err, defaultErrorFrameCount)
 			assert.NotNil(t, panicInfo)
 			assert.NotNil(t, panicInfo.Message)
 			assert.Equal(t, expectedMessage, panicInfo.Message)
@@ -45,6 +47,31 @@ func TestPanicFormattingInvokeResponse_Error(t *testing.T) {
 	assertPanicMessage(t, func() { panic(ive) }, ive.Error())
 }
 
+func TestWithStackDepth(t *testing.T) {
+	var recurse func(n int) error
+	recurse = func(n int) error {
+		if n == 0 {
+			panic("deep panic")
+		}
+		return recurse(n - 1)
+	}
+
+	for _, tc := range []struct {
+		options  []Option
+		expected int
+	}{
+		{nil, defaultErrorFrameCount},
+		{[]Option{WithStackDepth(5)}, 5},
+		{[]Option{WithStackDepth(64)}, 64},
+		{[]Option{WithStackDepth(0)}, defaultErrorFrameCount},
+	} {
+		handler := newHandler(func() error { return recurse(100) }, tc.options...)
+		_, invokeErr := callBytesHandlerFunc(context.Background(), []byte(`{}`), handler.handlerFunc, handler.stackDepth)
+		require.NotNil(t, invokeErr)
+		assert.Len(t, invokeErr.StackTrace, tc.expected)
+	}
+}
+
 func TestFormatFrame(t *testing.T) {
 	var tests = []struct {
 		inputPath     string
@@ -110,7 +137,7 @@ func TestRuntimeStackTrace(t *testing.T) {
 }
 
 func testRuntimeStackTrace(t *testing.T) {
-	panicInfo := getPanicInfo("Panic time!")
+	panicInfo := getPanicInfo("Panic time!", defaultErrorFrameCount)
 
 	assert.NotNil(t, panicInfo)
 	assert.NotNil(t, panicInfo.StackTrace)
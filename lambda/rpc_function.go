@@ -69,7 +69,7 @@ func (fn *Function) Ping(req *messages.PingRequest, response *messages.PingRespo
 func (fn *Function) Invoke(req *messages.InvokeRequest, response *messages.InvokeResponse) error {
 	defer func() {
 		if err := recover(); err != nil {
-			response.Error = lambdaPanicResponse(err)
+			response.Error = lambdaPanicResponse(err, fn.handler.stackDepth)
 		}
 	}()
 
@@ -0,0 +1,212 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package bunyan provides a [slog.Handler] that writes records in the Bunyan JSON format
+// for AWS Lambda functions.
+//
+// Each record is written as a line of JSON with the core Bunyan fields v, name, hostname, pid,
+// level, time and msg, with the level on the numeric Bunyan scale. Attributes become additional fields,
+// with groups as nested objects, and the requestId of the Lambda context is injected as requestId.
+//
+// See https://github.com/trentm/node-bunyan#core-fields
+package bunyan
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// Version is the Bunyan log format version of the records written by Handler.
+const Version = 0
+
+// Bunyan levels used for the level field.
+const (
+	LevelTrace = 10
+	LevelDebug = 20
+	LevelInfo  = 30
+	LevelWarn  = 40
+	LevelError = 50
+	LevelFatal = 60
+)
+
+// Level maps a slog level to the numeric Bunyan level. Levels below slog.LevelDebug map to LevelTrace,
+// and levels at or above slog.LevelError+4 map to LevelFatal, matching the TRACE and FATAL Lambda log levels.
+func Level(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError+4:
+		return LevelFatal
+	case level >= slog.LevelError:
+		return LevelError
+	case level >= slog.LevelWarn:
+		return LevelWarn
+	case level >= slog.LevelInfo:
+		return LevelInfo
+	case level >= slog.LevelDebug:
+		return LevelDebug
+	default:
+		return LevelTrace
+	}
+}
+
+// coreFields are the keys of the core Bunyan fields, which attributes cannot replace.
+var coreFields = map[string]bool{"v": true, "name": true, "hostname": true, "pid": true, "level": true, "time": true, "msg": true}
+
+// field is an attribute added with WithAttrs, with the groups it was added within.
+type field struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// Handler is a [slog.Handler] that writes Bunyan records to an io.Writer.
+type Handler struct {
+	opts     slog.HandlerOptions
+	name     string
+	hostname string
+	lock     *sync.Mutex
+	w        io.Writer
+	groups   []string
+	fields   []field
+}
+
+// NewHandler returns a Handler writing to w, using the given options, or the defaults if opts is nil.
+// The name field is the function name, or the name of the executable outside of Lambda.
+func NewHandler(w io.Writer, opts *slog.HandlerOptions) *Handler {
+	name := lambdacontext.FunctionName
+	if name == "" {
+		name = filepath.Base(os.Args[0])
+	}
+	hostname, _ := os.Hostname()
+	h := &Handler{name: name, hostname: hostname, lock: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	record := map[string]interface{}{}
+	for _, f := range h.fields {
+		h.addAttr(record, f.groups, f.attr)
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		h.addAttr(record, h.groups, attr)
+		return true
+	})
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		record["requestId"] = lc.AwsRequestID
+	}
+
+	record["v"] = Version
+	record["name"] = h.name
+	record["hostname"] = h.hostname
+	record["pid"] = os.Getpid()
+	record["level"] = Level(r.Level)
+	record["msg"] = r.Message
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	record["time"] = t.UTC().Format(time.RFC3339Nano)
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	_, err = h.w.Write(append(b, '\n'))
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.fields = h.fields[:len(h.fields):len(h.fields)]
+	for _, attr := range attrs {
+		clone.fields = append(clone.fields, field{groups: h.groups, attr: attr})
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+// addAttr adds attr to record within the nested objects of groups. Attributes replacing core fields are dropped.
+func (h *Handler) addAttr(record map[string]interface{}, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, a := range attr.Value.Group() {
+			h.addAttr(record, groups, a)
+		}
+		return
+	}
+	if (len(groups) == 0 && coreFields[attr.Key]) || (len(groups) > 0 && coreFields[groups[0]]) {
+		return
+	}
+	object := record
+	for _, group := range groups {
+		nested, ok := object[group].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			object[group] = nested
+		}
+		object = nested
+	}
+	object[attr.Key] = fieldValue(attr.Value)
+}
+
+// fieldValue converts v to a value encoded as JSON.
+func fieldValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return v.Any()
+	default:
+		return v.Any()
+	}
+}
@@ -0,0 +1,86 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package bunyan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, nil)).With("service", "orders").WithGroup("app").With("version", "1.0")
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "test-request"})
+	logger.InfoContext(ctx, "order processed", "count", 3, "error", errors.New("partial"))
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+	assert.Equal(t, float64(0), record["v"])
+	assert.NotEmpty(t, record["name"])
+	assert.Contains(t, record, "hostname")
+	assert.Equal(t, float64(os.Getpid()), record["pid"])
+	assert.Equal(t, float64(LevelInfo), record["level"])
+	assert.Equal(t, "order processed", record["msg"])
+	recordTime, err := time.Parse(time.RFC3339Nano, record["time"].(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), recordTime, time.Minute)
+	assert.Equal(t, "test-request", record["requestId"])
+	assert.Equal(t, "orders", record["service"])
+	assert.Equal(t, map[string]interface{}{"version": "1.0", "count": float64(3), "error": "partial"}, record["app"])
+}
+
+func TestHandler_CoreFields(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewHandler(&buf, nil)).Warn("core fields", "msg", "replaced", "level", "replaced", "pid", 0)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+	assert.Equal(t, "core fields", record["msg"])
+	assert.Equal(t, float64(LevelWarn), record["level"])
+	assert.Equal(t, float64(os.Getpid()), record["pid"])
+}
+
+func TestHandler_Enabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	logger.Info("dropped")
+	assert.Empty(t, buf.String())
+	logger.Error("written")
+	assert.NotEmpty(t, buf.String())
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		level    slog.Level
+		expected int
+	}{
+		{slog.LevelDebug - 4, LevelTrace},
+		{slog.LevelDebug, LevelDebug},
+		{slog.LevelInfo, LevelInfo},
+		{slog.LevelWarn, LevelWarn},
+		{slog.LevelError, LevelError},
+		{slog.LevelError + 4, LevelFatal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, Level(tt.level))
+		})
+	}
+}
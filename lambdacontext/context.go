@@ -13,6 +13,7 @@ import (
 	"context"
 	"os"
 	"strconv"
+	"time"
 )
 
 // LogGroupName is the name of the log group that contains the log streams of the current Lambda Function
@@ -83,6 +84,52 @@ type LambdaContext struct {
 	TenantID           string `json:",omitempty"`
 }
 
+// Merge returns a copy of lc overlaid with the non-zero fields of other, which take precedence.
+// The Env and Custom maps of the client contexts are merged key by key. Neither lc nor other is modified.
+func (lc *LambdaContext) Merge(other *LambdaContext) *LambdaContext {
+	merged := *lc
+	if other == nil {
+		merged.ClientContext.Env = mergeStrings(lc.ClientContext.Env, nil)
+		merged.ClientContext.Custom = mergeStrings(lc.ClientContext.Custom, nil)
+		return &merged
+	}
+	overlay(&merged.AwsRequestID, other.AwsRequestID)
+	overlay(&merged.InvokedFunctionArn, other.InvokedFunctionArn)
+	overlay(&merged.TenantID, other.TenantID)
+	overlay(&merged.Identity.CognitoIdentityID, other.Identity.CognitoIdentityID)
+	overlay(&merged.Identity.CognitoIdentityPoolID, other.Identity.CognitoIdentityPoolID)
+	overlay(&merged.ClientContext.Client.InstallationID, other.ClientContext.Client.InstallationID)
+	overlay(&merged.ClientContext.Client.AppTitle, other.ClientContext.Client.AppTitle)
+	overlay(&merged.ClientContext.Client.AppVersionCode, other.ClientContext.Client.AppVersionCode)
+	overlay(&merged.ClientContext.Client.AppPackageName, other.ClientContext.Client.AppPackageName)
+	merged.ClientContext.Env = mergeStrings(lc.ClientContext.Env, other.ClientContext.Env)
+	merged.ClientContext.Custom = mergeStrings(lc.ClientContext.Custom, other.ClientContext.Custom)
+	return &merged
+}
+
+// overlay sets *dst to src, unless src is empty.
+func overlay(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+// mergeStrings returns a new map with the entries of base and overlay, with overlay taking precedence.
+// It returns nil if both maps are nil.
+func mergeStrings(base, overlay map[string]string) map[string]string {
+	if base == nil && overlay == nil {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+	return merged
+}
+
 // An unexported type to be used as the key for types in this package.
 // This prevents collisions with keys defined in other packages.
 type key struct{}
@@ -92,6 +139,27 @@ type key struct{}
 // instead of using this key directly.
 var contextKey = &key{}
 
+// The key for the retry attempt number in Contexts.
+type retryAttemptKey struct{}
+
+// The key for the event source ARN in Contexts.
+type eventSourceARNKey struct{}
+
+// The key for the event type name in Contexts.
+type eventTypeKey struct{}
+
+// The key for the parent request ID in Contexts.
+type parentRequestIDKey struct{}
+
+// The key for the route name in Contexts.
+type routeKey struct{}
+
+// The key for the event time in Contexts.
+type eventTimeKey struct{}
+
+// The key for the OpenTelemetry span context in Contexts.
+type spanContextKey struct{}
+
 // NewContext returns a new Context that carries value lc.
 func NewContext(parent context.Context, lc *LambdaContext) context.Context {
 	return context.WithValue(parent, contextKey, lc)
@@ -102,3 +170,117 @@ func FromContext(ctx context.Context) (*LambdaContext, bool) {
 	lc, ok := ctx.Value(contextKey).(*LambdaContext)
 	return lc, ok
 }
+
+// WithRetryAttempt returns a new Context that carries the retry attempt number of the current invocation.
+// The Runtime API does not report retry attempts, so the lambda package sets it from the "retryAttempt" entry
+// of the client context's custom values, and middleware or custom runtimes may set it from other sources,
+// such as the event's own retry metadata.
+func WithRetryAttempt(parent context.Context, attempt int) context.Context {
+	return context.WithValue(parent, retryAttemptKey{}, attempt)
+}
+
+// RetryAttemptFromContext returns the retry attempt number of the current invocation, if any.
+// The number set by WithRetryAttempt takes precedence, otherwise the "retryAttempt" entry of the
+// client context's custom values is used.
+func RetryAttemptFromContext(ctx context.Context) (int, bool) {
+	if attempt, ok := ctx.Value(retryAttemptKey{}).(int); ok {
+		return attempt, true
+	}
+	if lc, ok := FromContext(ctx); ok {
+		if v, ok := lc.ClientContext.Custom["retryAttempt"]; ok {
+			if attempt, err := strconv.Atoi(v); err == nil {
+				return attempt, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// WithEventSourceARN returns a new Context that carries the ARN of the event source that triggered the invocation,
+// such as an SQS queue or a Kinesis stream. The lambda package sets it for events with an event source ARN.
+func WithEventSourceARN(parent context.Context, arn string) context.Context {
+	return context.WithValue(parent, eventSourceARNKey{}, arn)
+}
+
+// EventSourceARNFromContext returns the event source ARN stored in ctx, if any.
+func EventSourceARNFromContext(ctx context.Context) (string, bool) {
+	arn, ok := ctx.Value(eventSourceARNKey{}).(string)
+	return arn, ok && arn != ""
+}
+
+// WithEventType returns a new Context that carries the name of the Go type the invocation's event was decoded into,
+// such as "events.SQSEvent". The lambda package sets it for handlers that take an event argument.
+func WithEventType(parent context.Context, name string) context.Context {
+	return context.WithValue(parent, eventTypeKey{}, name)
+}
+
+// EventTypeFromContext returns the event type name stored in ctx, if any.
+func EventTypeFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(eventTypeKey{}).(string)
+	return name, ok && name != ""
+}
+
+// WithParentRequestID returns a new Context that carries the request ID of the invocation that caused this one,
+// such as the function that invoked this function, to trace fan-out across functions.
+func WithParentRequestID(parent context.Context, id string) context.Context {
+	return context.WithValue(parent, parentRequestIDKey{}, id)
+}
+
+// ParentRequestIDFromContext returns the parent request ID stored in ctx, if any.
+func ParentRequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(parentRequestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithRoute returns a new Context that carries the name of the route handling the invocation,
+// for handlers that dispatch events to sub-handlers, such as by event type.
+func WithRoute(parent context.Context, route string) context.Context {
+	return context.WithValue(parent, routeKey{}, route)
+}
+
+// RouteFromContext returns the route name stored in ctx, if any.
+func RouteFromContext(ctx context.Context) (string, bool) {
+	route, ok := ctx.Value(routeKey{}).(string)
+	return route, ok && route != ""
+}
+
+// WithEventTime returns a new Context that carries the time of the invocation's event,
+// such as the SentTimestamp of an SQS message or the ApproximateArrivalTimestamp of a Kinesis record.
+func WithEventTime(parent context.Context, t time.Time) context.Context {
+	return context.WithValue(parent, eventTimeKey{}, t)
+}
+
+// EventTimeFromContext returns the event time stored in ctx, if any.
+func EventTimeFromContext(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(eventTimeKey{}).(time.Time)
+	return t, ok && !t.IsZero()
+}
+
+// SpanContext identifies an OpenTelemetry span, such as the span of an invocation instrumented
+// with the AWS Distro for OpenTelemetry (ADOT).
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	// Recording is whether the span is recording, as reported by its IsRecording method.
+	Recording bool
+}
+
+// WithSpanContext returns a new Context that carries the OpenTelemetry span context sc.
+// The span context stored by the OpenTelemetry SDK cannot be read without depending on it,
+// so instrumented handlers copy it, such as with:
+//
+//	span := trace.SpanFromContext(ctx)
+//	ctx = lambdacontext.WithSpanContext(ctx, lambdacontext.SpanContext{
+//		TraceID:   span.SpanContext().TraceID(),
+//		SpanID:    span.SpanContext().SpanID(),
+//		Recording: span.IsRecording(),
+//	})
+func WithSpanContext(parent context.Context, sc SpanContext) context.Context {
+	return context.WithValue(parent, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the OpenTelemetry span context stored in ctx, if any.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok
+}
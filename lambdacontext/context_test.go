@@ -0,0 +1,45 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLambdaContextMerge(t *testing.T) {
+	base := &LambdaContext{
+		AwsRequestID:       "base-request",
+		InvokedFunctionArn: "base-arn",
+		Identity:           CognitoIdentity{CognitoIdentityID: "base-identity", CognitoIdentityPoolID: "base-pool"},
+		ClientContext:      ClientContext{Custom: map[string]string{"a": "1", "b": "2"}},
+	}
+	override := &LambdaContext{
+		AwsRequestID:  "override-request",
+		Identity:      CognitoIdentity{CognitoIdentityID: "override-identity"},
+		ClientContext: ClientContext{Custom: map[string]string{"b": "3"}},
+	}
+
+	merged := base.Merge(override)
+
+	assert.Equal(t, "override-request", merged.AwsRequestID)
+	assert.Equal(t, "base-arn", merged.InvokedFunctionArn)
+	assert.Equal(t, CognitoIdentity{CognitoIdentityID: "override-identity", CognitoIdentityPoolID: "base-pool"}, merged.Identity)
+	assert.Equal(t, map[string]string{"a": "1", "b": "3"}, merged.ClientContext.Custom)
+	assert.Nil(t, merged.ClientContext.Env)
+
+	// the inputs are left unmodified
+	assert.Equal(t, "base-request", base.AwsRequestID)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, base.ClientContext.Custom)
+
+	merged.ClientContext.Custom["c"] = "4"
+	assert.NotContains(t, base.ClientContext.Custom, "c")
+}
+
+func TestLambdaContextMerge_Nil(t *testing.T) {
+	base := &LambdaContext{AwsRequestID: "base-request"}
+	merged := base.Merge(nil)
+	assert.Equal(t, base, merged)
+	assert.NotSame(t, base, merged)
+}
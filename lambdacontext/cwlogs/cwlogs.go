@@ -0,0 +1,209 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package cwlogs provides a [slog.Handler] that sends records directly to Amazon CloudWatch Logs
+// with the PutLogEvents API, for AWS Lambda functions whose stdout log delivery is delayed.
+//
+// The AWS SDK is not a dependency of this package: the PutLogEvents call is provided by the caller
+// as a PutLogEventsFunc, typically adapting the PutLogEvents method of a CloudWatch Logs client.
+//
+// Records are encoded as JSON, with the requestId of the Lambda context injected, and buffered
+// until Flush is called or a batch reaches the PutLogEvents limits.
+package cwlogs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// PutLogEvents limits, see https://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_PutLogEvents.html
+const (
+	// MaxBatchEvents is the maximum number of events in a batch.
+	MaxBatchEvents = 10000
+	// MaxBatchBytes is the maximum size of a batch, counting EventOverheadBytes for each event.
+	MaxBatchBytes = 1048576
+	// MaxEventBytes is the maximum size of an event, counting EventOverheadBytes.
+	MaxEventBytes = 262144
+	// EventOverheadBytes is the size added to the message of each event when computing the size of a batch.
+	EventOverheadBytes = 26
+)
+
+// maxSequenceTokenRetries bounds the retries of a batch rejected for an invalid sequence token.
+const maxSequenceTokenRetries = 3
+
+// InputLogEvent is a log event sent to CloudWatch Logs.
+type InputLogEvent struct {
+	Message   string
+	Timestamp int64 // milliseconds since the Unix epoch
+}
+
+// PutLogEventsInput is the input of a PutLogEvents call.
+type PutLogEventsInput struct {
+	LogGroupName  string
+	LogStreamName string
+	LogEvents     []InputLogEvent
+	SequenceToken *string
+}
+
+// PutLogEventsOutput is the output of a PutLogEvents call.
+type PutLogEventsOutput struct {
+	NextSequenceToken *string
+}
+
+// PutLogEventsFunc sends a batch of log events to CloudWatch Logs. Implementations should return an
+// *InvalidSequenceTokenError for an InvalidSequenceTokenException, so that the batch is retried with the expected token.
+type PutLogEventsFunc func(ctx context.Context, input *PutLogEventsInput) (*PutLogEventsOutput, error)
+
+// InvalidSequenceTokenError reports that a batch was rejected because of an invalid sequence token.
+type InvalidSequenceTokenError struct {
+	ExpectedSequenceToken *string
+}
+
+func (e *InvalidSequenceTokenError) Error() string {
+	return "cwlogs: invalid sequence token"
+}
+
+// Handler is a [slog.Handler] that sends records to a CloudWatch Logs log stream.
+type Handler struct {
+	handler slog.Handler
+	sender  *sender
+}
+
+// sender buffers encoded records and sends them to CloudWatch Logs in batches.
+type sender struct {
+	// handleLock is held while a record is encoded, so that Write knows the time of the record.
+	handleLock sync.Mutex
+	time       time.Time
+
+	lock   sync.Mutex
+	put    PutLogEventsFunc
+	group  string
+	stream string
+	token  *string
+	events []InputLogEvent
+	size   int
+}
+
+// NewHandler returns a Handler sending records to the given log group and stream with put,
+// using the given options, or the defaults if opts is nil. Like NewLogHandler, records use the timestamp
+// and message keys unless opts sets ReplaceAttr. Messages exceeding MaxEventBytes are truncated,
+// and events are timestamped with the time of their record.
+func NewHandler(put PutLogEventsFunc, logGroup, logStream string, opts *slog.HandlerOptions) *Handler {
+	var handlerOpts slog.HandlerOptions
+	if opts != nil {
+		handlerOpts = *opts
+	}
+	if handlerOpts.ReplaceAttr == nil {
+		handlerOpts.ReplaceAttr = lambdacontext.ReplaceAttr
+	}
+	s := &sender{put: put, group: logGroup, stream: logStream}
+	return &Handler{handler: slog.NewJSONHandler(s, &handlerOpts), sender: s}
+}
+
+// Flush sends the buffered records. Records failing to send are kept buffered and sent by the next Flush.
+func (h *Handler) Flush(ctx context.Context) error {
+	h.sender.lock.Lock()
+	defer h.sender.lock.Unlock()
+	return h.sender.flush(ctx)
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler. Buffered records are sent when the batch is full,
+// in which case an error sending them is returned.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		r.AddAttrs(slog.String("requestId", lc.AwsRequestID))
+	}
+	h.sender.handleLock.Lock()
+	defer h.sender.handleLock.Unlock()
+	h.sender.time = r.Time
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{handler: h.handler.WithAttrs(attrs), sender: h.sender}
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{handler: h.handler.WithGroup(name), sender: h.sender}
+}
+
+// Write implements io.Writer, buffering p as an event and sending the batch first if p does not fit in it.
+func (s *sender) Write(p []byte) (int, error) {
+	message := string(p)
+	if n := len(message); n > 0 && message[n-1] == '\n' {
+		message = message[:n-1]
+	}
+	message = truncate(message, MaxEventBytes-EventOverheadBytes)
+	size := len(message) + EventOverheadBytes
+	timestamp := s.time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.events) == MaxBatchEvents || s.size+size > MaxBatchBytes {
+		if err := s.flush(context.Background()); err != nil {
+			return 0, err
+		}
+	}
+	s.events = append(s.events, InputLogEvent{Message: message, Timestamp: timestamp.UnixMilli()})
+	s.size += size
+	return len(p), nil
+}
+
+// truncate returns the first n bytes of message at most, without splitting a UTF-8 encoded rune.
+func truncate(message string, n int) string {
+	if len(message) <= n {
+		return message
+	}
+	for n > 0 && !utf8.RuneStart(message[n]) {
+		n--
+	}
+	return message[:n]
+}
+
+// flush sends the buffered events, retrying with the expected sequence token when the token is rejected.
+// Events are sent in chronological order, as required by PutLogEvents. s.lock must be held.
+func (s *sender) flush(ctx context.Context) error {
+	if len(s.events) == 0 {
+		return nil
+	}
+	sort.SliceStable(s.events, func(i, j int) bool { return s.events[i].Timestamp < s.events[j].Timestamp })
+	input := &PutLogEventsInput{LogGroupName: s.group, LogStreamName: s.stream, LogEvents: s.events}
+	for attempt := 0; ; attempt++ {
+		input.SequenceToken = s.token
+		output, err := s.put(ctx, input)
+		var invalidToken *InvalidSequenceTokenError
+		if errors.As(err, &invalidToken) && attempt < maxSequenceTokenRetries {
+			s.token = invalidToken.ExpectedSequenceToken
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cwlogs: sending %d events: %w", len(s.events), err)
+		}
+		if output != nil {
+			s.token = output.NextSequenceToken
+		}
+		break
+	}
+	s.events, s.size = nil, 0
+	return nil
+}
@@ -0,0 +1,172 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cwlogs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCloudWatchLogs records the batches sent to it, rejecting the ones with an unexpected sequence token.
+type fakeCloudWatchLogs struct {
+	token   string
+	batches []PutLogEventsInput
+	calls   int
+	err     error
+}
+
+func (f *fakeCloudWatchLogs) PutLogEvents(_ context.Context, input *PutLogEventsInput) (*PutLogEventsOutput, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.token != "" && (input.SequenceToken == nil || *input.SequenceToken != f.token) {
+		expected := f.token
+		return nil, &InvalidSequenceTokenError{ExpectedSequenceToken: &expected}
+	}
+	batch := *input
+	batch.LogEvents = append([]InputLogEvent(nil), input.LogEvents...)
+	f.batches = append(f.batches, batch)
+	next := "token-" + string(rune('a'+len(f.batches)))
+	f.token = next
+	return &PutLogEventsOutput{NextSequenceToken: &next}, nil
+}
+
+func TestHandler(t *testing.T) {
+	fake := &fakeCloudWatchLogs{}
+	handler := NewHandler(fake.PutLogEvents, "/aws/lambda/test", "stream", nil)
+	logger := slog.New(handler).With("service", "orders")
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "test-request"})
+	logger.InfoContext(ctx, "first")
+	logger.InfoContext(ctx, "second")
+	assert.Empty(t, fake.batches)
+
+	require.NoError(t, handler.Flush(ctx))
+	require.Len(t, fake.batches, 1)
+	batch := fake.batches[0]
+	assert.Equal(t, "/aws/lambda/test", batch.LogGroupName)
+	assert.Equal(t, "stream", batch.LogStreamName)
+	assert.Nil(t, batch.SequenceToken)
+	require.Len(t, batch.LogEvents, 2)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(batch.LogEvents[0].Message), &record))
+	assert.Equal(t, "first", record["message"])
+	assert.Equal(t, "test-request", record["requestId"])
+	assert.Equal(t, "orders", record["service"])
+	assert.NotZero(t, batch.LogEvents[0].Timestamp)
+	assert.LessOrEqual(t, batch.LogEvents[0].Timestamp, batch.LogEvents[1].Timestamp)
+
+	require.NoError(t, handler.Flush(ctx))
+	assert.Len(t, fake.batches, 1, "empty flushes send nothing")
+}
+
+func TestHandler_BatchLimits(t *testing.T) {
+	fake := &fakeCloudWatchLogs{}
+	// keep records small, so that the batches are bounded by their number of events
+	handler := NewHandler(fake.PutLogEvents, "group", "stream", &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey || attr.Key == slog.LevelKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	logger := slog.New(handler)
+
+	for i := 0; i < MaxBatchEvents+1; i++ {
+		logger.Info("record")
+	}
+	require.Len(t, fake.batches, 1)
+	assert.Len(t, fake.batches[0].LogEvents, MaxBatchEvents)
+
+	require.NoError(t, handler.Flush(context.Background()))
+	require.Len(t, fake.batches, 2)
+	assert.Len(t, fake.batches[1].LogEvents, 1)
+
+	large := make([]byte, MaxEventBytes)
+	for i := range large {
+		large[i] = 'x'
+	}
+	for i := 0; i < 5; i++ {
+		logger.Info(string(large))
+	}
+	require.NoError(t, handler.Flush(context.Background()))
+	for _, batch := range fake.batches[2:] {
+		size := 0
+		for _, event := range batch.LogEvents {
+			assert.LessOrEqual(t, len(event.Message)+EventOverheadBytes, MaxEventBytes)
+			size += len(event.Message) + EventOverheadBytes
+		}
+		assert.LessOrEqual(t, size, MaxBatchBytes)
+	}
+	assert.Greater(t, len(fake.batches), 3)
+}
+
+func TestHandler_RecordTime(t *testing.T) {
+	fake := &fakeCloudWatchLogs{}
+	handler := NewHandler(fake.PutLogEvents, "group", "stream", nil)
+
+	recorded := time.Date(2026, time.March, 14, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, handler.Handle(context.Background(), slog.NewRecord(recorded, slog.LevelInfo, "recorded", 0)))
+	require.NoError(t, handler.Flush(context.Background()))
+
+	require.Len(t, fake.batches, 1)
+	assert.Equal(t, recorded.UnixMilli(), fake.batches[0].LogEvents[0].Timestamp)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "abc", truncate("abcdef", 3))
+	// "é" is encoded as 2 bytes, and is not split
+	assert.Equal(t, "ab", truncate("abé", 3))
+	assert.Equal(t, "abé", truncate("abé", 4))
+
+	message := truncate(strings.Repeat("€", MaxEventBytes), MaxEventBytes-EventOverheadBytes)
+	assert.True(t, utf8.ValidString(message))
+	assert.LessOrEqual(t, len(message), MaxEventBytes-EventOverheadBytes)
+}
+
+func TestHandler_InvalidSequenceToken(t *testing.T) {
+	fake := &fakeCloudWatchLogs{token: "existing-token"}
+	handler := NewHandler(fake.PutLogEvents, "group", "stream", nil)
+	logger := slog.New(handler)
+
+	logger.Info("first")
+	require.NoError(t, handler.Flush(context.Background()))
+	require.Len(t, fake.batches, 1)
+	assert.Equal(t, "existing-token", *fake.batches[0].SequenceToken)
+	assert.Equal(t, 2, fake.calls)
+
+	logger.Info("second")
+	require.NoError(t, handler.Flush(context.Background()))
+	require.Len(t, fake.batches, 2)
+	assert.Equal(t, 3, fake.calls, "the next sequence token is used")
+}
+
+func TestHandler_Error(t *testing.T) {
+	fake := &fakeCloudWatchLogs{err: errors.New("throttled")}
+	handler := NewHandler(fake.PutLogEvents, "group", "stream", nil)
+	slog.New(handler).Info("first")
+
+	assert.ErrorContains(t, handler.Flush(context.Background()), "throttled")
+
+	fake.err = nil
+	require.NoError(t, handler.Flush(context.Background()))
+	require.Len(t, fake.batches, 1)
+	assert.Len(t, fake.batches[0].LogEvents, 1)
+}
@@ -0,0 +1,190 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package ecs provides a [slog.Handler] that writes records in the Elastic Common Schema (ECS) format
+// for AWS Lambda functions.
+//
+// Each record is written as a line of JSON with the ECS fields @timestamp, log.level, message and ecs.version,
+// with the nested objects of ECS field sets, such as {"log":{"level":"info"}}. Attributes become custom fields,
+// with groups as nested objects, and the requestId of the Lambda context is injected as labels.requestId.
+//
+// See https://www.elastic.co/guide/en/ecs/current/ecs-field-reference.html
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// Version is the ECS version of the records written by Handler.
+const Version = "8.11.0"
+
+// Level maps a slog level to the ECS log.level, the lowercase name of the level, such as "warn".
+// Levels below slog.LevelDebug map to "trace", and levels at or above slog.LevelError+4 map to "fatal",
+// matching the TRACE and FATAL Lambda log levels.
+func Level(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError+4:
+		return "fatal"
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warn"
+	case level >= slog.LevelInfo:
+		return "info"
+	case level >= slog.LevelDebug:
+		return "debug"
+	default:
+		return "trace"
+	}
+}
+
+// reservedFields are the top-level keys written by Handler, which attributes cannot replace.
+var reservedFields = map[string]bool{"@timestamp": true, "message": true, "log": true, "ecs": true, "labels": true}
+
+// field is an attribute added with WithAttrs, with the groups it was added within.
+type field struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// Handler is a [slog.Handler] that writes ECS records to an io.Writer.
+type Handler struct {
+	opts   slog.HandlerOptions
+	lock   *sync.Mutex
+	w      io.Writer
+	groups []string
+	fields []field
+}
+
+// NewHandler returns a Handler writing to w, using the given options, or the defaults if opts is nil.
+func NewHandler(w io.Writer, opts *slog.HandlerOptions) *Handler {
+	h := &Handler{lock: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	record := map[string]interface{}{}
+	for _, f := range h.fields {
+		h.addAttr(record, f.groups, f.attr)
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		h.addAttr(record, h.groups, attr)
+		return true
+	})
+
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	record["@timestamp"] = t.UTC().Format(time.RFC3339Nano)
+	record["message"] = r.Message
+	record["log"] = map[string]interface{}{"level": Level(r.Level)}
+	record["ecs"] = map[string]interface{}{"version": Version}
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		record["labels"] = map[string]interface{}{"requestId": lc.AwsRequestID}
+	}
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	_, err = h.w.Write(append(b, '\n'))
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.fields = h.fields[:len(h.fields):len(h.fields)]
+	for _, attr := range attrs {
+		clone.fields = append(clone.fields, field{groups: h.groups, attr: attr})
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+// addAttr adds attr to record within the nested objects of groups. Attributes replacing reserved fields are dropped.
+func (h *Handler) addAttr(record map[string]interface{}, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, a := range attr.Value.Group() {
+			h.addAttr(record, groups, a)
+		}
+		return
+	}
+	if (len(groups) == 0 && reservedFields[attr.Key]) || (len(groups) > 0 && reservedFields[groups[0]]) {
+		return
+	}
+	object := record
+	for _, group := range groups {
+		nested, ok := object[group].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			object[group] = nested
+		}
+		object = nested
+	}
+	object[attr.Key] = fieldValue(attr.Value)
+}
+
+// fieldValue converts v to a value encoded as JSON.
+func fieldValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return v.Any()
+	default:
+		return v.Any()
+	}
+}
@@ -0,0 +1,72 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package ecs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, nil)).With("service", "orders").WithGroup("app").With("version", "1.0")
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "test-request"})
+	logger.WarnContext(ctx, "order delayed", "count", 3)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+	timestamp, err := time.Parse(time.RFC3339Nano, record["@timestamp"].(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), timestamp, time.Minute)
+	assert.Equal(t, "order delayed", record["message"])
+	assert.Equal(t, map[string]interface{}{"level": "warn"}, record["log"])
+	assert.Equal(t, map[string]interface{}{"version": Version}, record["ecs"])
+	assert.Equal(t, map[string]interface{}{"requestId": "test-request"}, record["labels"])
+	assert.Equal(t, "orders", record["service"])
+	assert.Equal(t, map[string]interface{}{"version": "1.0", "count": float64(3)}, record["app"])
+}
+
+func TestHandler_ReservedFields(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewHandler(&buf, nil)).Info("reserved", "message", "replaced", slog.Group("log", "level", "replaced"))
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+	assert.Equal(t, "reserved", record["message"])
+	assert.Equal(t, map[string]interface{}{"level": "info"}, record["log"])
+	assert.NotContains(t, record, "labels")
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		level    slog.Level
+		expected string
+	}{
+		{slog.LevelDebug - 4, "trace"},
+		{slog.LevelDebug, "debug"},
+		{slog.LevelInfo, "info"},
+		{slog.LevelWarn, "warn"},
+		{slog.LevelError, "error"},
+		{slog.LevelError + 4, "fatal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, Level(tt.level))
+		})
+	}
+}
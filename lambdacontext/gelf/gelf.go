@@ -0,0 +1,195 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package gelf provides a [slog.Handler] that writes records in the Graylog Extended Log Format (GELF)
+// for AWS Lambda functions.
+//
+// Each record is written as a line of GELF 1.1 JSON. Attributes become additional fields prefixed
+// with an underscore, with group names joined by dots, and the requestId of the Lambda context is
+// injected as _requestId.
+//
+// See https://go2docs.graylog.org/current/getting_in_log_data/gelf.html
+package gelf
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// Version is the GELF specification version of the records written by Handler.
+const Version = "1.1"
+
+// Syslog severities used for the GELF level field.
+const (
+	SeverityError   = 3
+	SeverityWarning = 4
+	SeverityInfo    = 6
+	SeverityDebug   = 7
+)
+
+// Severity maps a slog level to the syslog severity used by GELF.
+func Severity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return SeverityError
+	case level >= slog.LevelWarn:
+		return SeverityWarning
+	case level >= slog.LevelInfo:
+		return SeverityInfo
+	default:
+		return SeverityDebug
+	}
+}
+
+// field is an additional GELF field, without its underscore prefix.
+type field struct {
+	key   string
+	value interface{}
+}
+
+// Handler is a [slog.Handler] that writes GELF records to an io.Writer.
+type Handler struct {
+	opts   slog.HandlerOptions
+	host   string
+	lock   *sync.Mutex
+	w      io.Writer
+	groups []string
+	fields []field
+}
+
+// NewHandler returns a Handler writing to w, using the given options, or the defaults if opts is nil.
+// The host field is the function name, or the hostname outside of Lambda.
+func NewHandler(w io.Writer, opts *slog.HandlerOptions) *Handler {
+	host := lambdacontext.FunctionName
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	h := &Handler{host: host, lock: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	message := map[string]interface{}{
+		"version":       Version,
+		"host":          h.host,
+		"short_message": r.Message,
+		"level":         Severity(r.Level),
+	}
+	if i := strings.IndexByte(r.Message, '\n'); i >= 0 {
+		message["short_message"] = r.Message[:i]
+		message["full_message"] = r.Message
+	}
+	if !r.Time.IsZero() {
+		message["timestamp"] = float64(r.Time.UnixNano()) / float64(time.Second)
+	}
+
+	// the capacity is clipped so that appending allocates, rather than writing into the fields shared with clones
+	fields := h.fields[:len(h.fields):len(h.fields)]
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = h.appendFields(fields, h.groups, attr)
+		return true
+	})
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		fields = append(fields, field{"requestId", lc.AwsRequestID})
+	}
+	for _, f := range fields {
+		// GELF reserves the _id field
+		if f.key != "id" {
+			message["_"+f.key] = f.value
+		}
+	}
+
+	b, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	_, err = h.w.Write(append(b, '\n'))
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.fields = h.fields[:len(h.fields):len(h.fields)]
+	for _, attr := range attrs {
+		clone.fields = h.appendFields(clone.fields, h.groups, attr)
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+// appendFields appends attr to fields, flattening groups into dotted keys.
+func (h *Handler) appendFields(fields []field, groups []string, attr slog.Attr) []field {
+	attr.Value = attr.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, a := range attr.Value.Group() {
+			fields = h.appendFields(fields, groups, a)
+		}
+		return fields
+	}
+
+	key := strings.Join(append(groups[:len(groups):len(groups)], attr.Key), ".")
+	return append(fields, field{key, fieldValue(attr.Value)})
+}
+
+// fieldValue converts v to a string or number, the only additional field types allowed by GELF.
+func fieldValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	default:
+		return v.String()
+	}
+}
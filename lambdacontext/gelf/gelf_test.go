@@ -0,0 +1,97 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package gelf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, nil)).WithGroup("app").With("version", "1.0")
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "test-request"})
+	logger.InfoContext(ctx, "order processed", "count", 3, "id", "ignored")
+
+	var message map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &message))
+
+	assert.Equal(t, "1.1", message["version"])
+	assert.NotEmpty(t, message["host"])
+	assert.Equal(t, "order processed", message["short_message"])
+	assert.NotContains(t, message, "full_message")
+	assert.Equal(t, float64(SeverityInfo), message["level"])
+	assert.IsType(t, float64(0), message["timestamp"])
+	assert.Equal(t, "test-request", message["_requestId"])
+	assert.Equal(t, "1.0", message["_app.version"])
+	assert.Equal(t, float64(3), message["_app.count"])
+	assert.NotContains(t, message, "_id")
+}
+
+func TestHandler_Concurrent(t *testing.T) {
+	var buf bytes.Buffer
+	// WithAttrs leaves spare capacity in the fields shared by the records of the logger
+	logger := slog.New(NewHandler(&buf, nil)).With("a", 1, "b", 2, "c", 3)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info(strconv.Itoa(worker), "worker", worker)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var message map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &message))
+		assert.Equal(t, message["short_message"], strconv.Itoa(int(message["_worker"].(float64))))
+		assert.Equal(t, float64(3), message["_c"])
+	}
+}
+
+func TestHandler_FullMessage(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewHandler(&buf, nil)).Error("request failed\nstack trace")
+
+	var message map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &message))
+
+	assert.Equal(t, "request failed", message["short_message"])
+	assert.Equal(t, "request failed\nstack trace", message["full_message"])
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		level    slog.Level
+		expected int
+	}{
+		{slog.LevelDebug, SeverityDebug},
+		{slog.LevelInfo, SeverityInfo},
+		{slog.LevelWarn, SeverityWarning},
+		{slog.LevelError, SeverityError},
+		{slog.LevelError + 4, SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, Severity(tt.level))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// restored is set to 1 by MarkRestored.
+var restored int32
+
+// inflight is the number of invocations started by InvokeStarted and not yet ended by InvokeEnded.
+var inflight int32
+
+// invokeStarts holds the time each invocation started by InvokeStarted and not yet ended began, by request ID.
+var invokeStarts sync.Map
+
+// invokeHook is a function registered with OnInvokeStart or OnInvokeEnd, identified by its address for unregistering.
+type invokeHook struct {
+	fn func(context.Context)
+}
+
+var (
+	invokeHooksLock  sync.RWMutex
+	invokeStartHooks []*invokeHook
+	invokeEndHooks   []*invokeHook
+)
+
+// OnInvokeStart registers fn to be called at the start of each invocation, before the handler runs.
+// The context passed to fn carries the invocation's LambdaContext.
+// It returns a function unregistering fn, for callers that do not live as long as the process.
+func OnInvokeStart(fn func(context.Context)) (unregister func()) {
+	return registerInvokeHook(&invokeStartHooks, fn)
+}
+
+// OnInvokeEnd registers fn to be called at the end of each invocation, after the handler returns and its response,
+// including a streamed response body, or its error has been sent, including when the handler panics.
+// The context passed to fn carries the invocation's LambdaContext.
+// It returns a function unregistering fn, for callers that do not live as long as the process.
+func OnInvokeEnd(fn func(context.Context)) (unregister func()) {
+	return registerInvokeHook(&invokeEndHooks, fn)
+}
+
+// registerInvokeHook adds fn to hooks, returning a function removing it.
+func registerInvokeHook(hooks *[]*invokeHook, fn func(context.Context)) func() {
+	hook := &invokeHook{fn: fn}
+	invokeHooksLock.Lock()
+	defer invokeHooksLock.Unlock()
+	*hooks = append(*hooks, hook)
+	return func() {
+		invokeHooksLock.Lock()
+		defer invokeHooksLock.Unlock()
+		// a new slice is built, as runInvokeHooks iterates over the current one without holding the lock
+		remaining := make([]*invokeHook, 0, len(*hooks))
+		for _, h := range *hooks {
+			if h != hook {
+				remaining = append(remaining, h)
+			}
+		}
+		*hooks = remaining
+	}
+}
+
+// InvokeStarted runs the functions registered with OnInvokeStart.
+// It is called by the lambda package, and only needs to be called directly by custom runtimes or tests.
+func InvokeStarted(ctx context.Context) {
+	atomic.AddInt32(&inflight, 1)
+	if lc, ok := FromContext(ctx); ok {
+		invokeStarts.Store(lc.AwsRequestID, time.Now())
+	}
+	runInvokeHooks(ctx, &invokeStartHooks)
+}
+
+// InvokeEnded runs the functions registered with OnInvokeEnd.
+// It is called by the lambda package, and only needs to be called directly by custom runtimes or tests.
+func InvokeEnded(ctx context.Context) {
+	runInvokeHooks(ctx, &invokeEndHooks)
+	if lc, ok := FromContext(ctx); ok {
+		invokeStarts.Delete(lc.AwsRequestID)
+	}
+	atomic.AddInt32(&inflight, -1)
+}
+
+// invocationTimeout returns the time the invocation in ctx had between InvokeStarted and its deadline,
+// which is the function's configured timeout.
+func invocationTimeout(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	lc, ok := FromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	start, ok := invokeStarts.Load(lc.AwsRequestID)
+	if !ok {
+		return 0, false
+	}
+	timeout := deadline.Sub(start.(time.Time))
+	return timeout, timeout > 0
+}
+
+func runInvokeHooks(ctx context.Context, hooks *[]*invokeHook) {
+	invokeHooksLock.RLock()
+	registered := *hooks
+	invokeHooksLock.RUnlock()
+	for _, hook := range registered {
+		hook.fn(ctx)
+	}
+}
+
+// MarkRestored records that the execution environment was restored from a SnapStart snapshot.
+// Call it from the function's restore hook.
+func MarkRestored() {
+	atomic.StoreInt32(&restored, 1)
+}
+
+// Restored reports whether MarkRestored has been called.
+func Restored() bool {
+	return atomic.LoadInt32(&restored) == 1
+}
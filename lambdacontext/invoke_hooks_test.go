@@ -0,0 +1,46 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvokeHooks(t *testing.T) {
+	var calls []string
+	OnInvokeStart(func(ctx context.Context) {
+		lc, _ := FromContext(ctx)
+		calls = append(calls, "start "+lc.AwsRequestID)
+	})
+	OnInvokeEnd(func(ctx context.Context) {
+		lc, _ := FromContext(ctx)
+		calls = append(calls, "end "+lc.AwsRequestID)
+	})
+
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "hooked-request"})
+	InvokeStarted(ctx)
+	InvokeEnded(ctx)
+
+	assert.Equal(t, []string{"start hooked-request", "end hooked-request"}, calls)
+}
+
+func TestInvokeHooks_Unregister(t *testing.T) {
+	var calls int
+	unregisterStart := OnInvokeStart(func(context.Context) { calls++ })
+	unregisterEnd := OnInvokeEnd(func(context.Context) { calls++ })
+
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "hooked-request"})
+	InvokeStarted(ctx)
+	InvokeEnded(ctx)
+	assert.Equal(t, 2, calls)
+
+	unregisterStart()
+	unregisterEnd()
+	unregisterEnd()
+	InvokeStarted(ctx)
+	InvokeEnded(ctx)
+	assert.Equal(t, 2, calls)
+}
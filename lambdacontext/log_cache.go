@@ -0,0 +1,102 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// CacheStats counts the hits and misses of an in-process cache during each invocation, and logs them
+// when the invocation ends, as signaled by the invocation hooks called by the lambda package:
+//
+//	{"message":"cache stats","cache":{"hits":9,"misses":1,"ratio":0.9}}
+//
+// The counts are kept per request ID of the context, so that concurrent invocations are counted separately,
+// and are reset after they are logged. Nothing is logged for invocations without hits or misses.
+type CacheStats struct {
+	logger     *slog.Logger
+	unregister func()
+	lock       sync.Mutex
+	counts     map[string]*cacheCounts
+}
+
+// cacheCounts are the hits and misses of an invocation.
+type cacheCounts struct {
+	hits   int
+	misses int
+}
+
+// NewCacheStats returns CacheStats logging with logger at the end of each invocation, until it is closed.
+// Use a logger created by NewLogger so that the record carries the requestId of the invocation.
+func NewCacheStats(logger *slog.Logger) *CacheStats {
+	s := &CacheStats{logger: logger, counts: map[string]*cacheCounts{}}
+	s.unregister = OnInvokeEnd(s.Flush)
+	return s
+}
+
+// RecordHit counts a cache hit in the invocation of ctx.
+func (s *CacheStats) RecordHit(ctx context.Context) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.countsOf(ctx).hits++
+}
+
+// RecordMiss counts a cache miss in the invocation of ctx.
+func (s *CacheStats) RecordMiss(ctx context.Context) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.countsOf(ctx).misses++
+}
+
+// countsOf returns the counts of the invocation of ctx. s.lock must be held.
+func (s *CacheStats) countsOf(ctx context.Context) *cacheCounts {
+	requestID := cacheRequestID(ctx)
+	c, ok := s.counts[requestID]
+	if !ok {
+		c = &cacheCounts{}
+		s.counts[requestID] = c
+	}
+	return c
+}
+
+// cacheRequestID returns the request ID of ctx, or "" outside of an invocation.
+func cacheRequestID(ctx context.Context) string {
+	if lc, ok := FromContext(ctx); ok {
+		return lc.AwsRequestID
+	}
+	return ""
+}
+
+// Flush logs the counts of the invocation of ctx and resets them. It is called when each invocation ends,
+// and only needs to be called directly to log the counts early.
+func (s *CacheStats) Flush(ctx context.Context) {
+	requestID := cacheRequestID(ctx)
+	s.lock.Lock()
+	c, ok := s.counts[requestID]
+	delete(s.counts, requestID)
+	s.lock.Unlock()
+	if !ok || c.hits+c.misses == 0 {
+		return
+	}
+	s.logger.InfoContext(ctx, "cache stats",
+		slog.Group("cache",
+			slog.Int("hits", c.hits),
+			slog.Int("misses", c.misses),
+			slog.Float64("ratio", float64(c.hits)/float64(c.hits+c.misses)),
+		),
+	)
+}
+
+// Close stops logging the counts at the end of each invocation, for CacheStats that do not live
+// as long as the process. Counts not yet logged are discarded.
+func (s *CacheStats) Close() {
+	s.unregister()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.counts = map[string]*cacheCounts{}
+}
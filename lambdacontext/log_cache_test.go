@@ -0,0 +1,84 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheStats(t *testing.T) {
+	var buf bytes.Buffer
+	logger, ctx := newTestLogger(&buf)
+
+	stats := NewCacheStats(logger)
+	defer stats.Close()
+	InvokeStarted(ctx)
+	stats.RecordHit(ctx)
+	stats.RecordHit(ctx)
+	stats.RecordHit(ctx)
+	stats.RecordMiss(ctx)
+	InvokeEnded(ctx)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "cache stats", logOutput["message"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+	assert.Equal(t, map[string]interface{}{"hits": float64(3), "misses": float64(1), "ratio": 0.75}, logOutput["cache"])
+
+	// the counts are reset for the next invocation
+	buf.Reset()
+	InvokeStarted(ctx)
+	InvokeEnded(ctx)
+	assert.Empty(t, buf.String())
+
+	stats.RecordMiss(ctx)
+	stats.Flush(ctx)
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, map[string]interface{}{"hits": float64(0), "misses": float64(1), "ratio": float64(0)}, logOutput["cache"])
+}
+
+func TestCacheStats_ConcurrentInvocations(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := newTestLogger(&buf)
+
+	stats := NewCacheStats(logger)
+	defer stats.Close()
+	ctx1 := NewContext(context.Background(), &LambdaContext{AwsRequestID: "request-aaa"})
+	ctx2 := NewContext(context.Background(), &LambdaContext{AwsRequestID: "request-bbb"})
+	InvokeStarted(ctx1)
+	InvokeStarted(ctx2)
+	stats.RecordHit(ctx1)
+	stats.RecordMiss(ctx2)
+	stats.RecordMiss(ctx2)
+	InvokeEnded(ctx1)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "request-aaa", logOutput["requestId"])
+	assert.Equal(t, map[string]interface{}{"hits": float64(1), "misses": float64(0), "ratio": float64(1)}, logOutput["cache"])
+
+	buf.Reset()
+	InvokeEnded(ctx2)
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "request-bbb", logOutput["requestId"])
+	assert.Equal(t, map[string]interface{}{"hits": float64(0), "misses": float64(2), "ratio": float64(0)}, logOutput["cache"])
+}
+
+func TestCacheStats_Close(t *testing.T) {
+	registered := len(invokeEndHooks)
+	stats := NewCacheStats(slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil)))
+	assert.Len(t, invokeEndHooks, registered+1)
+
+	stats.Close()
+	assert.Len(t, invokeEndHooks, registered)
+}
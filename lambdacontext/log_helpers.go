@@ -0,0 +1,414 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+// StartSpan starts timing a named span of work within an invocation.
+// Calling the returned function logs the span name and its elapsed duration in milliseconds as durationMs.
+// Use a logger created by NewLogger so that the record carries the requestId of ctx.
+func StartSpan(ctx context.Context, logger *slog.Logger, name string) func() {
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		logger.InfoContext(ctx, name+" took "+elapsed.Round(time.Millisecond).String(),
+			slog.String("span", name),
+			slog.Float64("durationMs", durationMillis(elapsed)),
+		)
+	}
+}
+
+// LogValidationError logs a WARN record for an event payload failing validation, with a validation group
+// holding the JSON path of the invalid value and the reason it is invalid:
+//
+//	{"message":"validation failed","validation":{"path":"$.order.items[0].quantity","reason":"must be positive"}}
+//
+// Use a logger created by NewLogger so that the record carries the requestId of ctx.
+func LogValidationError(ctx context.Context, logger *slog.Logger, path, reason string) {
+	logger.WarnContext(ctx, "validation failed",
+		slog.Group("validation",
+			slog.String("path", path),
+			slog.String("reason", reason),
+		),
+	)
+}
+
+// LogFlag logs an INFO record for the evaluation of a feature flag, with a featureFlag group holding
+// the name of the flag, the value it evaluated to and the reason for the value, to audit flag decisions:
+//
+//	{"message":"feature flag evaluated","featureFlag":{"name":"new-checkout","value":true,"reason":"targeting match"}}
+//
+// Use a logger created by NewLogger so that the record carries the requestId of ctx.
+func LogFlag(ctx context.Context, logger *slog.Logger, flag string, value any, reason string) {
+	logger.InfoContext(ctx, "feature flag evaluated",
+		slog.Group("featureFlag",
+			slog.String("name", flag),
+			slog.Any("value", value),
+			slog.String("reason", reason),
+		),
+	)
+}
+
+// LogIdempotency logs an INFO record for the idempotency check of a request, with an idempotency group holding
+// the idempotency key and whether it was a hit, a request already processed whose stored result is returned,
+// rather than a fresh execution:
+//
+//	{"message":"idempotency key hit","idempotency":{"key":"order-123","hit":true}}
+//
+// Use a logger created by NewLogger so that the record carries the requestId of ctx.
+func LogIdempotency(ctx context.Context, logger *slog.Logger, key string, hit bool) {
+	msg := "idempotency key miss"
+	if hit {
+		msg = "idempotency key hit"
+	}
+	logger.InfoContext(ctx, msg,
+		slog.Group("idempotency",
+			slog.String("key", key),
+			slog.Bool("hit", hit),
+		),
+	)
+}
+
+// LogHTTPCall logs a record for the result of an outbound HTTP call, with an http group holding its method, url,
+// status code as status, and latency in milliseconds as latencyMs. The record is WARN for 5xx status codes,
+// and INFO otherwise:
+//
+//	{"message":"GET https://api.example.com/orders 200","http":{"method":"GET","url":"https://api.example.com/orders","status":200,"latencyMs":42.5}}
+//
+// Use a logger created by NewLogger so that the record carries the requestId of ctx.
+func LogHTTPCall(ctx context.Context, logger *slog.Logger, method, url string, status int, latency time.Duration) {
+	level := slog.LevelInfo
+	if status >= 500 && status < 600 {
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(ctx, level, method+" "+url+" "+strconv.Itoa(status),
+		slog.Group("http",
+			slog.String("method", method),
+			slog.String("url", url),
+			slog.Int("status", status),
+			slog.Float64("latencyMs", durationMillis(latency)),
+		),
+	)
+}
+
+// LogDynamoCapacity logs an INFO record for the capacity consumed by a DynamoDB operation, with a dynamodb group
+// holding the table name as table, and the consumed read and write capacity units as readCapacityUnits and
+// writeCapacityUnits, as returned in the ConsumedCapacity of responses:
+//
+//	{"message":"dynamodb capacity consumed","dynamodb":{"table":"orders","readCapacityUnits":0.5,"writeCapacityUnits":1}}
+//
+// Use a logger created by NewLogger so that the record carries the requestId of ctx.
+func LogDynamoCapacity(ctx context.Context, logger *slog.Logger, table string, rcu, wcu float64) {
+	logger.InfoContext(ctx, "dynamodb capacity consumed",
+		slog.Group("dynamodb",
+			slog.String("table", table),
+			slog.Float64("readCapacityUnits", rcu),
+			slog.Float64("writeCapacityUnits", wcu),
+		),
+	)
+}
+
+// LogRetry logs a WARN record for a retried attempt of an operation, with the attempt number as attempt,
+// the delay before the next attempt in milliseconds as delayMs, and the error of the attempt as error,
+// with its type name, as reported by the Lambda runtime for function errors, as errorType.
+// Use a logger created by NewLogger so that the record carries the requestId of ctx.
+func LogRetry(ctx context.Context, logger *slog.Logger, attempt int, delay time.Duration, err error) {
+	attrs := []slog.Attr{
+		slog.Int("attempt", attempt),
+		slog.Float64("delayMs", durationMillis(delay)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()), slog.String("errorType", errorType(err)))
+	}
+	logger.LogAttrs(ctx, slog.LevelWarn, "retrying after attempt "+strconv.Itoa(attempt)+" failed", attrs...)
+}
+
+// LogIfCancelled reports whether ctx is cancelled or past its deadline, logging a WARN record with msg
+// and the cause of the cancellation as cause when it is, so that handlers can log and return early:
+//
+//	if lambdacontext.LogIfCancelled(ctx, logger, "stopping before processing remaining records") {
+//		return ctx.Err()
+//	}
+//
+// Nothing is logged while ctx is live. Use a logger created by NewLogger so that the record carries the requestId of ctx.
+func LogIfCancelled(ctx context.Context, logger *slog.Logger, msg string) bool {
+	if ctx.Err() == nil {
+		return false
+	}
+	logger.LogAttrs(ctx, slog.LevelWarn, msg, slog.String("cause", context.Cause(ctx).Error()))
+	return true
+}
+
+// CommandOutputLimit is the number of bytes of stdout and stderr logged by CaptureCommandOutput.
+// The default of 4 KiB keeps its records well below the maximum size of a CloudWatch Logs event.
+var CommandOutputLimit = 4096
+
+// limitedBuffer holds the first limit bytes written to it, discarding the rest.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write implements io.Writer.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:room])
+		b.truncated = true
+	} else {
+		b.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+// CaptureCommandOutput runs cmd, and logs its stdout and stderr, each truncated to CommandOutputLimit bytes, as stdout
+// and stderr, with the name of the command as command and its exit code as exitCode, instead of writing its output
+// unstructured to the function's logs. The record is INFO when the command succeeds and ERROR otherwise,
+// and includes truncated when output was truncated. Writers already set as cmd.Stdout or cmd.Stderr still receive
+// the full output. When they are the same writer, as with exec.Cmd.CombinedOutput, the output of both is logged
+// in the order it was written as output. Use a logger created by NewLogger so that the record carries the requestId
+// of ctx.
+//
+// It returns the exit code of the command, or -1 when the command could not be run, which is logged as an ERROR.
+func CaptureCommandOutput(ctx context.Context, logger *slog.Logger, cmd *exec.Cmd) int {
+	limit := max(CommandOutputLimit, 0)
+	stdout := &limitedBuffer{limit: limit}
+	stderr := &limitedBuffer{limit: limit}
+	combined := cmd.Stdout != nil && sameWriter(cmd.Stdout, cmd.Stderr)
+	if combined {
+		// a single writer, which exec.Cmd does not write to concurrently
+		cmd.Stdout = teeWriter(cmd.Stdout, stdout)
+		cmd.Stderr = cmd.Stdout
+	} else {
+		cmd.Stdout = teeWriter(cmd.Stdout, stdout)
+		cmd.Stderr = teeWriter(cmd.Stderr, stderr)
+	}
+
+	exitCode, level := 0, slog.LevelInfo
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			logger.LogAttrs(ctx, slog.LevelError, "command failed to run",
+				slog.String("command", filepath.Base(cmd.Path)), slog.String("error", err.Error()))
+			return -1
+		}
+		exitCode, level = exitErr.ExitCode(), slog.LevelError
+	}
+
+	attrs := []slog.Attr{
+		slog.String("command", filepath.Base(cmd.Path)),
+		slog.Int("exitCode", exitCode),
+	}
+	if combined {
+		attrs = append(attrs, slog.String("output", stdout.buf.String()))
+	} else {
+		attrs = append(attrs, slog.String("stdout", stdout.buf.String()), slog.String("stderr", stderr.buf.String()))
+	}
+	if stdout.truncated || stderr.truncated {
+		attrs = append(attrs, slog.Bool("truncated", true))
+	}
+	logger.LogAttrs(ctx, level, "command exited with code "+strconv.Itoa(exitCode), attrs...)
+	return exitCode
+}
+
+// teeWriter returns a writer duplicating its writes to w, if set, and to capture.
+func teeWriter(w io.Writer, capture io.Writer) io.Writer {
+	if w == nil {
+		return capture
+	}
+	return io.MultiWriter(w, capture)
+}
+
+// sameWriter reports whether a and b are the same writer, like exec.Cmd does, treating writers
+// that cannot be compared as different.
+func sameWriter(a, b io.Writer) (same bool) {
+	defer func() { _ = recover() }()
+	return a == b
+}
+
+// Go runs fn in a new goroutine, recovering from a panic in fn instead of letting it crash the process.
+// A recovered panic is logged as an ERROR record with the panic value as panic and the stack of the goroutine
+// as stack. Use a logger created by NewLogger so that the record carries the requestId of ctx.
+func Go(ctx context.Context, logger *slog.Logger, fn func()) {
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				logger.LogAttrs(ctx, slog.LevelError, "recovered from panic in goroutine",
+					slog.String("panic", fmt.Sprint(v)),
+					slog.String("stack", string(debug.Stack())),
+				)
+			}
+		}()
+		fn()
+	}()
+}
+
+// AuditEvent is an action recorded for compliance by Audit.
+type AuditEvent struct {
+	Actor    string    // who performed the action, such as a user or role ARN
+	Action   string    // what was done, such as "orders.delete"
+	Resource string    // what it was done to, such as an order ID
+	Outcome  string    // the result, such as "success" or "denied"
+	Time     time.Time // when it was done, or the time Audit is called if zero
+}
+
+// Audit logs an INFO record for event with the fixed schema of audit records, separate from operational records:
+//
+//	{"timestamp":"...","level":"INFO","message":"audit","type":"audit","actor":"...","action":"...","resource":"...","outcome":"..."}
+//
+// The timestamp is the time of the event. The record is logged regardless of the level of logger, so that
+// audit records are not lost to log level configuration. Use a logger created by NewLogger so that the record
+// carries the requestId of ctx.
+func Audit(ctx context.Context, logger *slog.Logger, event AuditEvent) error {
+	t := event.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	r := slog.NewRecord(t, slog.LevelInfo, "audit", 0)
+	r.AddAttrs(
+		slog.String("type", "audit"),
+		slog.String("actor", event.Actor),
+		slog.String("action", event.Action),
+		slog.String("resource", event.Resource),
+		slog.String("outcome", event.Outcome),
+	)
+	return logger.Handler().Handle(ctx, r)
+}
+
+// errorType returns the name of the type of err, dereferencing pointers.
+func errorType(err error) string {
+	t := reflect.TypeOf(err)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// durationMillis converts d to fractional milliseconds.
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// StructAttrs returns the attrs of the exported fields of the struct v, or of the struct v points to,
+// for logging values such as configuration. Fields are named by their log tag, or by their name when untagged,
+// and fields tagged log:"-" are skipped:
+//
+//	type Config struct {
+//		Table    string `log:"table"`
+//		Password string `log:"-"`
+//		Retry    struct {
+//			Attempts int `log:"attempts"`
+//		} `log:"retry"`
+//	}
+//
+// Nested structs become groups, and the fields of embedded structs without a tag are included as fields of v.
+// It returns nil when v is not a struct or a non-nil pointer to one.
+func StructAttrs(v any) []slog.Attr {
+	rv := reflect.ValueOf(v)
+	visiting := map[structPointer]bool{}
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		visiting[structPointer{rv.Type(), rv.Pointer()}] = true
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	return structAttrs(rv, visiting)
+}
+
+// structPointer identifies a pointer dereferenced by StructAttrs, to detect cycles.
+type structPointer struct {
+	t    reflect.Type
+	addr uintptr
+}
+
+// structAttrs returns the attrs of the exported fields of the struct rv.
+// visiting holds the pointers dereferenced to reach rv.
+func structAttrs(rv reflect.Value, visiting map[structPointer]bool) []slog.Attr {
+	var attrs []slog.Attr
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup("log")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		value := rv.Field(i)
+		if field.Anonymous && !tagged && value.Kind() == reflect.Struct {
+			attrs = append(attrs, structAttrs(value, visiting)...)
+			continue
+		}
+		key := field.Name
+		if tag != "" {
+			key = tag
+		}
+		if attr, ok := structFieldAttr(key, value, visiting); ok {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+// structFieldAttr returns the attr of a struct field, with nested structs as groups.
+// Values implementing slog.LogValuer, including through a pointer receiver, are left to it.
+// It returns false for a pointer back to a struct being visited, which would never end.
+func structFieldAttr(key string, value reflect.Value, visiting map[structPointer]bool) (slog.Attr, bool) {
+	var visited []structPointer
+	defer func() {
+		for _, p := range visited {
+			delete(visiting, p)
+		}
+	}()
+	for value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.Struct &&
+		!value.Type().Implements(logValuerType) {
+		p := structPointer{value.Type(), value.Pointer()}
+		if visiting[p] {
+			return slog.Attr{}, false
+		}
+		visiting[p] = true
+		visited = append(visited, p)
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Struct && !value.Type().Implements(logValuerType) && value.Type() != timeType {
+		return slog.Attr{Key: key, Value: slog.GroupValue(structAttrs(value, visiting)...)}, true
+	}
+	return slog.Any(key, value.Interface()), true
+}
+
+var (
+	logValuerType = reflect.TypeOf((*slog.LogValuer)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// lazyValue is a slog.LogValuer calling a function to compute its value.
+type lazyValue func() any
+
+// LogValue implements slog.LogValuer.
+func (f lazyValue) LogValue() slog.Value {
+	return slog.AnyValue(f())
+}
+
+// Lazy returns a value computed by fn when the record is encoded, so that fn only runs for records
+// that are emitted. Use it for expensive values, such as serialized large structs:
+//
+//	logger.Debug("event received", "event", lambdacontext.Lazy(func() any { return dump(event) }))
+func Lazy(fn func() any) slog.Value {
+	return slog.AnyValue(lazyValue(fn))
+}
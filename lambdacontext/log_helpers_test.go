@@ -0,0 +1,490 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLogger returns a Lambda-aware JSON logger writing to buf, and a context carrying requestId.
+func newTestLogger(buf *bytes.Buffer) (*slog.Logger, context.Context) {
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: ReplaceAttr}
+	logger := slog.New(&lambdaHandler{handler: slog.NewJSONHandler(buf, opts)})
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	return logger, ctx
+}
+
+func TestStartSpan(t *testing.T) {
+	var buf bytes.Buffer
+	logger, ctx := newTestLogger(&buf)
+
+	end := StartSpan(ctx, logger, "db.query")
+	time.Sleep(time.Millisecond)
+	end()
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+
+	assert.Equal(t, "db.query", logOutput["span"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+	require.Contains(t, logOutput, "durationMs")
+	assert.GreaterOrEqual(t, logOutput["durationMs"].(float64), float64(0))
+	assert.Contains(t, logOutput["message"], "db.query took ")
+}
+
+func TestLogValidationError(t *testing.T) {
+	var buf bytes.Buffer
+	logger, ctx := newTestLogger(&buf)
+
+	LogValidationError(ctx, logger, "$.order.items[0].quantity", "must be positive")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+
+	assert.Equal(t, "WARN", logOutput["level"])
+	assert.Equal(t, "validation failed", logOutput["message"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+	assert.Equal(t, map[string]interface{}{
+		"path":   "$.order.items[0].quantity",
+		"reason": "must be positive",
+	}, logOutput["validation"])
+}
+
+func TestLogFlag(t *testing.T) {
+	var buf bytes.Buffer
+	logger, ctx := newTestLogger(&buf)
+
+	LogFlag(ctx, logger, "new-checkout", true, "targeting match")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+
+	assert.Equal(t, "INFO", logOutput["level"])
+	assert.Equal(t, "feature flag evaluated", logOutput["message"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+	assert.Equal(t, map[string]interface{}{
+		"name":   "new-checkout",
+		"value":  true,
+		"reason": "targeting match",
+	}, logOutput["featureFlag"])
+}
+
+func TestLogIdempotency(t *testing.T) {
+	for _, hit := range []bool{true, false} {
+		t.Run(strconv.FormatBool(hit), func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, ctx := newTestLogger(&buf)
+
+			LogIdempotency(ctx, logger, "order-123", hit)
+
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+
+			assert.Equal(t, "INFO", logOutput["level"])
+			assert.Equal(t, "test-request", logOutput["requestId"])
+			assert.Equal(t, map[string]interface{}{
+				"key": "order-123",
+				"hit": hit,
+			}, logOutput["idempotency"])
+		})
+	}
+}
+
+func TestLogDynamoCapacity(t *testing.T) {
+	var buf bytes.Buffer
+	logger, ctx := newTestLogger(&buf)
+
+	LogDynamoCapacity(ctx, logger, "orders", 0.5, 1)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+
+	assert.Equal(t, "INFO", logOutput["level"])
+	assert.Equal(t, "dynamodb capacity consumed", logOutput["message"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+	assert.Equal(t, map[string]interface{}{
+		"table":              "orders",
+		"readCapacityUnits":  0.5,
+		"writeCapacityUnits": float64(1),
+	}, logOutput["dynamodb"])
+}
+
+func TestLogHTTPCall(t *testing.T) {
+	tests := []struct {
+		status int
+		level  string
+	}{
+		{status: 200, level: "INFO"},
+		{status: 404, level: "INFO"},
+		{status: 500, level: "WARN"},
+	}
+	for _, tc := range tests {
+		t.Run(strconv.Itoa(tc.status), func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, ctx := newTestLogger(&buf)
+
+			LogHTTPCall(ctx, logger, "GET", "https://api.example.com/orders", tc.status, 1500*time.Microsecond)
+
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+
+			assert.Equal(t, tc.level, logOutput["level"])
+			assert.Equal(t, "GET https://api.example.com/orders "+strconv.Itoa(tc.status), logOutput["message"])
+			assert.Equal(t, "test-request", logOutput["requestId"])
+			assert.Equal(t, map[string]interface{}{
+				"method":    "GET",
+				"url":       "https://api.example.com/orders",
+				"status":    float64(tc.status),
+				"latencyMs": 1.5,
+			}, logOutput["http"])
+		})
+	}
+}
+
+func TestGo(t *testing.T) {
+	var buf syncBuffer
+	opts := &slog.HandlerOptions{ReplaceAttr: ReplaceAttr}
+	logger := slog.New(&lambdaHandler{handler: slog.NewJSONHandler(&buf, opts)})
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+
+	done := make(chan struct{})
+	Go(ctx, logger, func() {
+		defer close(done)
+		panic("boom")
+	})
+	<-done
+	for deadline := time.Now().Add(time.Second); len(buf.Bytes()) == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "ERROR", logOutput["level"])
+	assert.Equal(t, "boom", logOutput["panic"])
+	assert.Contains(t, logOutput["stack"], "TestGo")
+	assert.Equal(t, "test-request", logOutput["requestId"])
+}
+
+func TestAudit(t *testing.T) {
+	var buf bytes.Buffer
+	opts := &slog.HandlerOptions{Level: slog.LevelError, ReplaceAttr: ReplaceAttr}
+	logger := slog.New(&lambdaHandler{handler: slog.NewJSONHandler(&buf, opts)})
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+
+	deleted := time.Date(2026, time.March, 14, 15, 9, 26, 0, time.UTC)
+	require.NoError(t, Audit(ctx, logger, AuditEvent{
+		Actor:    "arn:aws:iam::123456789012:role/admin",
+		Action:   "orders.delete",
+		Resource: "order-123",
+		Outcome:  "success",
+		Time:     deleted,
+	}))
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, map[string]interface{}{
+		"timestamp": "2026-03-14T15:09:26Z",
+		"level":     "INFO",
+		"message":   "audit",
+		"type":      "audit",
+		"actor":     "arn:aws:iam::123456789012:role/admin",
+		"action":    "orders.delete",
+		"resource":  "order-123",
+		"outcome":   "success",
+		"requestId": "test-request",
+	}, logOutput)
+}
+
+// throttlingError is an error type for testing errorType.
+type throttlingError struct{}
+
+func (*throttlingError) Error() string { return "rate exceeded" }
+
+func TestLogRetry(t *testing.T) {
+	var buf bytes.Buffer
+	logger, ctx := newTestLogger(&buf)
+
+	LogRetry(ctx, logger, 2, 1500*time.Millisecond, &throttlingError{})
+	LogRetry(ctx, logger, 3, 0, nil)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var failed, noError map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &failed))
+	require.NoError(t, json.Unmarshal(lines[1], &noError))
+
+	assert.Equal(t, "WARN", failed["level"])
+	assert.Equal(t, "retrying after attempt 2 failed", failed["message"])
+	assert.Equal(t, float64(2), failed["attempt"])
+	assert.Equal(t, float64(1500), failed["delayMs"])
+	assert.Equal(t, "rate exceeded", failed["error"])
+	assert.Equal(t, "throttlingError", failed["errorType"])
+	assert.Equal(t, "test-request", failed["requestId"])
+
+	assert.Equal(t, float64(3), noError["attempt"])
+	assert.NotContains(t, noError, "error")
+	assert.NotContains(t, noError, "errorType")
+}
+
+func TestLogIfCancelled(t *testing.T) {
+	t.Run("live", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, ctx := newTestLogger(&buf)
+
+		assert.False(t, LogIfCancelled(ctx, logger, "stopping"))
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, ctx := newTestLogger(&buf)
+		ctx, cancel := context.WithCancelCause(ctx)
+		cancel(errors.New("shutting down"))
+
+		assert.True(t, LogIfCancelled(ctx, logger, "stopping"))
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+		assert.Equal(t, "WARN", logOutput["level"])
+		assert.Equal(t, "stopping", logOutput["message"])
+		assert.Equal(t, "shutting down", logOutput["cause"])
+		assert.Equal(t, "test-request", logOutput["requestId"])
+	})
+
+	t.Run("past deadline", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, ctx := newTestLogger(&buf)
+		ctx, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+		defer cancel()
+
+		assert.True(t, LogIfCancelled(ctx, logger, "stopping"))
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+		assert.Equal(t, context.DeadlineExceeded.Error(), logOutput["cause"])
+		assert.Equal(t, "test-request", logOutput["requestId"])
+	})
+}
+
+func TestCaptureCommandOutput(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, ctx := newTestLogger(&buf)
+
+		exitCode := CaptureCommandOutput(ctx, logger, exec.Command("sh", "-c", "echo converted; echo 'low quality' >&2"))
+		assert.Equal(t, 0, exitCode)
+
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+		assert.Equal(t, "INFO", logOutput["level"])
+		assert.Equal(t, "sh", logOutput["command"])
+		assert.Equal(t, float64(0), logOutput["exitCode"])
+		assert.Equal(t, "converted\n", logOutput["stdout"])
+		assert.Equal(t, "low quality\n", logOutput["stderr"])
+		assert.Equal(t, "test-request", logOutput["requestId"])
+		assert.NotContains(t, logOutput, "truncated")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, ctx := newTestLogger(&buf)
+
+		exitCode := CaptureCommandOutput(ctx, logger, exec.Command("sh", "-c", "exit 3"))
+		assert.Equal(t, 3, exitCode)
+
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+		assert.Equal(t, "ERROR", logOutput["level"])
+		assert.Equal(t, float64(3), logOutput["exitCode"])
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, ctx := newTestLogger(&buf)
+
+		var full bytes.Buffer
+		cmd := exec.Command("sh", "-c", "head -c 10000 /dev/zero | tr '\\0' x")
+		cmd.Stdout = &full
+		original := CommandOutputLimit
+		t.Cleanup(func() { CommandOutputLimit = original })
+		CommandOutputLimit = 100
+		assert.Equal(t, 0, CaptureCommandOutput(ctx, logger, cmd))
+		assert.Equal(t, 10000, full.Len())
+
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+		assert.Len(t, logOutput["stdout"], 100)
+		assert.Equal(t, true, logOutput["truncated"])
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, ctx := newTestLogger(&buf)
+
+		var full bytes.Buffer
+		cmd := exec.Command("sh", "-c", "for i in 1 2 3; do echo out$i; echo err$i >&2; done")
+		cmd.Stdout = &full
+		cmd.Stderr = &full
+		assert.Equal(t, 0, CaptureCommandOutput(ctx, logger, cmd))
+		assert.Equal(t, "out1\nerr1\nout2\nerr2\nout3\nerr3\n", full.String())
+
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+		assert.Equal(t, full.String(), logOutput["output"])
+		assert.NotContains(t, logOutput, "stdout")
+		assert.NotContains(t, logOutput, "stderr")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, ctx := newTestLogger(&buf)
+
+		exitCode := CaptureCommandOutput(ctx, logger, exec.Command("command-that-does-not-exist"))
+		assert.Equal(t, -1, exitCode)
+		assert.Contains(t, buf.String(), "command failed to run")
+	})
+}
+
+func TestLazy(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(withWriter(&buf))
+	calls := 0
+	expensive := Lazy(func() any {
+		calls++
+		return map[string]int{"items": 3}
+	})
+
+	logger.Debug("disabled", "payload", expensive)
+	assert.Equal(t, 0, calls)
+	assert.Zero(t, buf.Len())
+
+	logger.Info("enabled", "payload", expensive)
+	assert.Equal(t, 1, calls)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, map[string]interface{}{"items": float64(3)}, logOutput["payload"])
+}
+
+func TestStructAttrs(t *testing.T) {
+	type Retry struct {
+		Attempts int           `log:"attempts"`
+		Backoff  time.Duration `log:"backoff"`
+	}
+	type Base struct {
+		Region string `log:"region"`
+	}
+	type Config struct {
+		Base
+		Table    string `log:"table"`
+		Password string `log:"-"`
+		Timeout  int
+		Retry    Retry      `log:"retry"`
+		Fallback *Retry     `log:"fallback"`
+		Created  time.Time  `log:"created"`
+		internal string     // unexported fields are skipped
+		Optional *time.Time `log:"optional"`
+	}
+	created := time.Date(2026, time.March, 14, 0, 0, 0, 0, time.UTC)
+	config := &Config{
+		Base:     Base{Region: "us-east-1"},
+		Table:    "orders",
+		Password: "secret",
+		Timeout:  30,
+		Retry:    Retry{Attempts: 3, Backoff: time.Second},
+		Fallback: &Retry{Attempts: 1},
+		Created:  created,
+		internal: "internal",
+	}
+
+	attrs := StructAttrs(config)
+	keys := make([]string, len(attrs))
+	for i, attr := range attrs {
+		keys[i] = attr.Key
+	}
+	assert.Equal(t, []string{"region", "table", "Timeout", "retry", "fallback", "created", "optional"}, keys)
+	assert.Equal(t, "orders", attrs[1].Value.String())
+	assert.Equal(t, int64(30), attrs[2].Value.Int64())
+	assert.Equal(t, slog.KindGroup, attrs[3].Value.Kind())
+	assert.Equal(t, []slog.Attr{slog.Int("attempts", 3), slog.Duration("backoff", time.Second)}, attrs[3].Value.Group())
+	assert.Equal(t, slog.KindGroup, attrs[4].Value.Kind())
+	assert.Equal(t, created, attrs[5].Value.Time())
+
+	var buf bytes.Buffer
+	logger, ctx := newTestLogger(&buf)
+	logger.LogAttrs(ctx, slog.LevelInfo, "config loaded", StructAttrs(config)...)
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, map[string]interface{}{"attempts": float64(3), "backoff": float64(time.Second)}, logOutput["retry"])
+	assert.NotContains(t, buf.String(), "secret")
+
+	assert.Nil(t, StructAttrs("not a struct"))
+	assert.Nil(t, StructAttrs((*Config)(nil)))
+}
+
+// credentials is a type redacting itself with a pointer receiver LogValue, for testing StructAttrs.
+type credentials struct {
+	Secret string
+}
+
+func (*credentials) LogValue() slog.Value { return slog.StringValue("REDACTED") }
+
+// node is a type that can point back to itself, for testing StructAttrs.
+type node struct {
+	Name string `log:"name"`
+	Next *node  `log:"next"`
+}
+
+func TestStructAttrs_PointerLogValuer(t *testing.T) {
+	type Config struct {
+		Credentials *credentials `log:"credentials"`
+	}
+
+	var buf bytes.Buffer
+	logger, ctx := newTestLogger(&buf)
+	logger.LogAttrs(ctx, slog.LevelInfo, "config loaded", StructAttrs(Config{Credentials: &credentials{Secret: "secret"}})...)
+
+	assert.Contains(t, buf.String(), `"credentials":"REDACTED"`)
+	assert.NotContains(t, buf.String(), "secret")
+}
+
+func TestStructAttrs_Cycle(t *testing.T) {
+	first := &node{Name: "first"}
+	first.Next = &node{Name: "second", Next: first}
+
+	var buf bytes.Buffer
+	logger, ctx := newTestLogger(&buf)
+	logger.LogAttrs(ctx, slog.LevelInfo, "list", StructAttrs(first)...)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "first", logOutput["name"])
+	assert.Equal(t, map[string]interface{}{"name": "second"}, logOutput["next"])
+
+	shared := &node{Name: "shared"}
+	type Pair struct {
+		Left  *node `log:"left"`
+		Right *node `log:"right"`
+	}
+	attrs := StructAttrs(Pair{Left: shared, Right: shared})
+	require.Len(t, attrs, 2)
+	assert.Equal(t, attrs[0].Value.Group(), attrs[1].Value.Group())
+}
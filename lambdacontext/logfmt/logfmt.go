@@ -0,0 +1,174 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package logfmt provides a [slog.Handler] that writes records in logfmt format,
+// space-separated key=value pairs, for AWS Lambda functions.
+//
+// Like the handlers returned by lambdacontext.NewLogHandler, it names the time and message
+// keys timestamp and message, and injects the requestId of the Lambda context into each record.
+package logfmt
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// Handler is a [slog.Handler] that writes logfmt records to an io.Writer.
+type Handler struct {
+	opts         slog.HandlerOptions
+	lock         *sync.Mutex
+	w            io.Writer
+	groups       []string
+	preformatted []byte
+}
+
+// NewHandler returns a Handler writing to w, using the given options, or the defaults if opts is nil.
+func NewHandler(w io.Writer, opts *slog.HandlerOptions) *Handler {
+	h := &Handler{lock: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	if !r.Time.IsZero() {
+		h.appendAttr(&buf, nil, slog.Time(slog.TimeKey, r.Time.UTC()))
+	}
+	h.appendAttr(&buf, nil, slog.Any(slog.LevelKey, r.Level))
+	h.appendAttr(&buf, nil, slog.String(slog.MessageKey, r.Message))
+	if lc, ok := lambdacontext.FromContext(ctx); ok && !hasAttr(r, "requestId") {
+		h.appendAttr(&buf, nil, slog.String("requestId", lc.AwsRequestID))
+	}
+	buf.Write(h.preformatted)
+	r.Attrs(func(attr slog.Attr) bool {
+		h.appendAttr(&buf, h.groups, attr)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	_, err := h.w.Write(bytes.TrimPrefix(buf.Bytes(), []byte{' '}))
+	return err
+}
+
+// hasAttr reports whether r has an attr with key, such as a requestId added by lambdacontext.Fanout.
+func hasAttr(r slog.Record, key string) bool {
+	found := false
+	r.Attrs(func(attr slog.Attr) bool {
+		found = attr.Key == key
+		return !found
+	})
+	return found
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	buf := bytes.NewBuffer(append([]byte(nil), h.preformatted...))
+	for _, attr := range attrs {
+		h.appendAttr(buf, h.groups, attr)
+	}
+	clone.preformatted = buf.Bytes()
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]string(nil), h.groups...), name)
+	return &clone
+}
+
+// appendAttr appends " key=value" to buf, flattening groups into dotted keys.
+func (h *Handler) appendAttr(buf *bytes.Buffer, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() != slog.KindGroup {
+		if h.opts.ReplaceAttr != nil {
+			attr = h.opts.ReplaceAttr(groups, attr)
+			attr.Value = attr.Value.Resolve()
+		}
+		if len(groups) == 0 {
+			attr = lambdacontext.ReplaceAttr(groups, attr)
+		}
+	}
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, a := range attr.Value.Group() {
+			h.appendAttr(buf, groups, a)
+		}
+		return
+	}
+
+	buf.WriteByte(' ')
+	buf.WriteString(formatKey(append(groups[:len(groups):len(groups)], attr.Key)))
+	buf.WriteByte('=')
+	buf.WriteString(formatValue(attr.Value))
+}
+
+func formatKey(path []string) string {
+	key := strings.Join(path, ".")
+	if needsQuoting(key) {
+		return strconv.Quote(key)
+	}
+	return key
+}
+
+func formatValue(v slog.Value) string {
+	var s string
+	switch v.Kind() {
+	case slog.KindTime:
+		s = v.Time().Format(time.RFC3339Nano)
+	default:
+		s = v.String()
+	}
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// needsQuoting reports whether s must be quoted to be parsed back as a single logfmt value.
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package logfmt
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, nil))
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "test-request"})
+	logger.InfoContext(ctx, "order processed", "orderId", "order-123", "count", 3)
+
+	line := buf.String()
+	assert.True(t, strings.HasPrefix(line, "timestamp="), line)
+	assert.True(t, strings.HasSuffix(line, "\n"), line)
+	assert.Contains(t, line, ` level=INFO message="order processed" requestId=test-request orderId=order-123 count=3`)
+}
+
+func TestHandler_Quoting(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"plain", "value", `key=value`},
+		{"space", "two words", `key="two words"`},
+		{"equals", "a=b", `key="a=b"`},
+		{"quote", `say "hi"`, `key="say \"hi\""`},
+		{"newline", "line1\nline2", `key="line1\nline2"`},
+		{"empty", "", `key=""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.New(NewHandler(&buf, nil)).Info("msg", "key", tt.value)
+			assert.True(t, strings.HasSuffix(buf.String(), " "+tt.expected+"\n"), buf.String())
+		})
+	}
+}
+
+func TestHandler_Groups(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, nil)).With("service", "orders").WithGroup("app").With("version", "1.0")
+	logger.Info("msg", slog.Group("req", slog.String("method", "GET")))
+
+	assert.Contains(t, buf.String(), ` service=orders app.version=1.0 app.req.method=GET`)
+}
+
+func TestHandler_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	assert.NotContains(t, buf.String(), "dropped")
+	assert.Contains(t, buf.String(), "level=WARN message=kept")
+}
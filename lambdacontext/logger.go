@@ -7,25 +7,82 @@ package lambdacontext
 
 import (
 	"context"
+	"io"
+	"log"
 	"log/slog"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
-// logFormat is the log format from AWS_LAMBDA_LOG_FORMAT (TEXT or JSON)
+// logFormat is the log format from AWS_LAMBDA_LOG_FORMAT (Text or JSON, case-insensitive)
 var logFormat = os.Getenv("AWS_LAMBDA_LOG_FORMAT")
 
 // logLevel is the log level from AWS_LAMBDA_LOG_LEVEL
 var logLevel = os.Getenv("AWS_LAMBDA_LOG_LEVEL")
 
-// field represents a Lambda context field to include in log records.
-type field struct {
+// Field represents a value to include in log records, such as a Lambda context
+// field or a value carried by the invocation's context.
+type Field struct {
 	key   string
 	value func(*LambdaContext) string
+	attr  func(context.Context) (slog.Value, bool)
+}
+
+// resolve returns the value of the field for the given context, and whether it should be emitted.
+func (f Field) resolve(ctx context.Context, lc *LambdaContext) (slog.Value, bool) {
+	if f.attr != nil {
+		return f.attr(ctx)
+	}
+	if lc == nil {
+		return slog.Value{}, false
+	}
+	v := f.value(lc)
+	return slog.StringValue(v), v != ""
 }
 
 // logOptions holds configuration for the Lambda log handler.
 type logOptions struct {
-	fields []field
+	fields         []Field
+	fieldSet       func(context.Context) []Field
+	messageAsArray bool
+	messageKey     string
+	emptyMessage   EmptyMessagePolicy
+	requestIDKey   string
+	batch          bool
+	aggregate      bool
+	keyOrder       func(a, b string) int
+	suppress       []*regexp.Regexp
+	condition      func(context.Context) bool
+	suppressInit   bool
+	backoff        *errorBackoff
+	onDrop         func(string, slog.Record)
+	recordSize     bool
+	attrCount      bool
+	verbosity      bool
+	bufferDepth    bool
+	nearTimeout    time.Duration
+	location       *time.Location
+	heartbeat      time.Duration
+	diagnostic     bool
+	invokeStart    []func(context.Context) // invocation hooks of the handler, see lambdaHandler.onInvokeStart
+	invokeEnd      []func(context.Context)
+	maxAttrs       int
+	checksumKey    []byte
+	promotedKeys   []string
+	toggles        map[string]func() bool
+	xray           *xrayAnnotator
+	transforms     []attrTransform
+	keySanitizer   func(string) string
+	duplicates     *duplicateDetector
+	levelFiles     *levelFiles
+	sink           Sink
+	level          string // used when AWS_LAMBDA_LOG_LEVEL is unset
+	format         string // used when AWS_LAMBDA_LOG_FORMAT is unset
+	writer         io.Writer
 }
 
 // LogOption is a functional option for configuring the Lambda log handler.
@@ -34,14 +91,248 @@ type LogOption func(*logOptions)
 // WithFunctionARN includes the invoked function ARN in log records.
 func WithFunctionARN() LogOption {
 	return func(o *logOptions) {
-		o.fields = append(o.fields, field{"functionArn", func(lc *LambdaContext) string { return lc.InvokedFunctionArn }})
+		o.fields = append(o.fields, Field{key: "functionArn", value: func(lc *LambdaContext) string { return lc.InvokedFunctionArn }})
 	}
 }
 
 // WithTenantID includes the tenant ID in log records (for multi-tenant functions).
 func WithTenantID() LogOption {
 	return func(o *logOptions) {
-		o.fields = append(o.fields, field{"tenantId", func(lc *LambdaContext) string { return lc.TenantID }})
+		o.fields = append(o.fields, Field{key: "tenantId", value: func(lc *LambdaContext) string { return lc.TenantID }})
+	}
+}
+
+// WithFields includes the given fields in log records.
+func WithFields(fields ...Field) LogOption {
+	return func(o *logOptions) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+// FieldSet is a set of fields included together, such as the fields relevant to an event source.
+type FieldSet []Field
+
+// WithFieldSetSelector includes the fields returned by selector for the context of each record,
+// in addition to the fields configured with WithFields, allowing a function handling several event sources
+// to include different fields for each of them:
+//
+//	sets := map[string]lambdacontext.FieldSet{
+//		"events.SQSEvent":                {lambdacontext.FieldEventSourceARN()},
+//		"events.APIGatewayProxyRequest": {lambdacontext.FieldRemainingPercent()},
+//	}
+//	lambdacontext.WithFieldSetSelector(func(ctx context.Context) []lambdacontext.Field {
+//		eventType, _ := lambdacontext.EventTypeFromContext(ctx)
+//		return sets[eventType]
+//	})
+//
+// The keys of selected fields are not reported by HandlerFields.
+func WithFieldSetSelector(selector func(context.Context) []Field) LogOption {
+	return func(o *logOptions) {
+		o.fieldSet = selector
+	}
+}
+
+// WithMessageAsArray emits multi-line messages as an array of lines when using the JSON format.
+// Messages without newlines are still emitted as a single string.
+func WithMessageAsArray() LogOption {
+	return func(o *logOptions) {
+		o.messageAsArray = true
+	}
+}
+
+// WithMessageKey sets the key under which the log message is emitted, for log indices
+// that expect the message under a key other than "message", such as "msg" or "body".
+func WithMessageKey(key string) LogOption {
+	return func(o *logOptions) {
+		o.messageKey = key
+	}
+}
+
+// EmptyMessagePolicy is how records with an empty message are emitted, see WithEmptyMessagePolicy.
+type EmptyMessagePolicy int
+
+const (
+	// EmptyMessageKeep emits empty messages as an empty string. It is the default.
+	EmptyMessageKeep EmptyMessagePolicy = iota
+	// EmptyMessageOmit omits the message key from records with an empty message.
+	EmptyMessageOmit
+	// EmptyMessagePlaceholder emits empty messages as "(no message)".
+	EmptyMessagePlaceholder
+)
+
+// emptyMessagePlaceholder is the message emitted for empty messages with EmptyMessagePlaceholder.
+const emptyMessagePlaceholder = "(no message)"
+
+// WithEmptyMessagePolicy sets how records with an empty message are emitted, for log validators
+// that reject an empty message. Records are emitted with an empty message by default.
+func WithEmptyMessagePolicy(policy EmptyMessagePolicy) LogOption {
+	return func(o *logOptions) {
+		o.emptyMessage = policy
+	}
+}
+
+// WithRequestIDKey sets the key under which the request ID is emitted, such as "aws.requestId",
+// to avoid colliding with a requestId attr logged by an HTTP framework. The key is emitted as given.
+func WithRequestIDKey(key string) LogOption {
+	return func(o *logOptions) {
+		o.requestIDKey = key
+	}
+}
+
+// WithSchemaVersion includes a constant schemaVersion field in every log record,
+// allowing downstream parsers to detect changes to the log schema.
+func WithSchemaVersion(version string) LogOption {
+	return WithFields(constantField("schemaVersion", slog.StringValue(version)))
+}
+
+// constantField returns a Field that always emits v under key.
+func constantField(key string, v slog.Value) Field {
+	return Field{key: key, attr: func(context.Context) (slog.Value, bool) { return v, true }}
+}
+
+// WithTimezoneField includes a constant tz field of "UTC" in every log record, and renders timestamps in UTC
+// even when the TZ environment variable sets another local time zone, so that readers of logs from several
+// regions need not guess the zone of timestamps. Use WithTimezone for another zone.
+func WithTimezoneField() LogOption {
+	return WithTimezone(time.UTC)
+}
+
+// WithTimezone renders timestamps in loc, and includes the name of loc, such as "Europe/Paris", as tz in every log record.
+func WithTimezone(loc *time.Location) LogOption {
+	return func(o *logOptions) {
+		o.location = loc
+		o.fields = append(o.fields, constantField("tz", slog.StringValue(loc.String())))
+	}
+}
+
+// WithBatchPerInvocation buffers the JSON records of each invocation and writes them as a single JSON array
+// when the invocation ends, reducing the number of lines written to CloudWatch Logs.
+// Records logged outside of an invocation, including records of invocations that did not call InvokeStarted
+// and records logged after InvokeEnded, are written immediately. Use Flush to write buffered records early.
+// It has no effect with the text format.
+func WithBatchPerInvocation() LogOption {
+	return func(o *logOptions) {
+		o.batch = true
+	}
+}
+
+// WithKeyOrder orders the keys of JSON records, including those of nested groups, using cmp.
+// The timestamp, level and message keys remain first, in that order.
+// Ordering requires decoding and re-encoding each record, so it is best suited to tests and low-volume functions.
+// It has no effect with the text format.
+func WithKeyOrder(cmp func(a, b string) int) LogOption {
+	return func(o *logOptions) {
+		o.keyOrder = cmp
+	}
+}
+
+// FieldRetryAttempt includes the retry attempt number of the invocation as retryAttempt.
+// The value is read from the context, see RetryAttemptFromContext. Nothing is emitted when it is absent.
+func FieldRetryAttempt() Field {
+	return Field{key: "retryAttempt", attr: func(ctx context.Context) (slog.Value, bool) {
+		attempt, ok := RetryAttemptFromContext(ctx)
+		return slog.IntValue(attempt), ok
+	}}
+}
+
+// WithSuppressPattern drops records whose message matches re. It may be used multiple times to suppress several patterns.
+// Matching records are dropped regardless of their level, including ERROR records.
+func WithSuppressPattern(re *regexp.Regexp) LogOption {
+	return func(o *logOptions) {
+		o.suppress = append(o.suppress, re)
+	}
+}
+
+// WithConditionalLogging drops records below ERROR for invocations whose context does not satisfy predicate,
+// such as those not flagged for debugging by an upstream sampler. ERROR and above are always logged.
+func WithConditionalLogging(predicate func(context.Context) bool) LogOption {
+	return func(o *logOptions) {
+		o.condition = predicate
+	}
+}
+
+// WithSuppressInit drops records below ERROR until the first invocation starts, as signaled by the invocation hooks
+// called by the lambda package, to keep the records logged during initialization, such as by package-level
+// variables and provisioned concurrency initialization, out of production logs. ERROR and above are always logged.
+func WithSuppressInit() LogOption {
+	return func(o *logOptions) {
+		o.suppressInit = true
+	}
+}
+
+// WithRecordSize includes the encoded size of each record in bytes as recordBytes.
+// The size is measured before recordBytes itself is appended, so it slightly undercounts the final record.
+func WithRecordSize() LogOption {
+	return func(o *logOptions) {
+		o.recordSize = true
+	}
+}
+
+// WithChecksum appends an HMAC-SHA256 of each encoded record, keyed by key, as the hex-encoded _hmac field,
+// allowing downstream consumers to detect truncated or tampered records.
+// As the HMAC cannot cover itself, it is computed over the record as encoded before _hmac was appended:
+// to verify a JSON record, remove the trailing `,"_hmac":"..."` member, and for the text format
+// the trailing ` _hmac=...`, and compute the HMAC of the remaining bytes.
+func WithChecksum(key []byte) LogOption {
+	return func(o *logOptions) {
+		o.checksumKey = key
+	}
+}
+
+// WithMaxAttrs keeps only the first n attrs of each record, and includes the number of attrs omitted as attrsOmitted.
+// The requestId and the fields configured with WithFields and similar options are never omitted, nor counted.
+// Attrs added with Logger.With are not limited. Values of n below 1 are ignored.
+func WithMaxAttrs(n int) LogOption {
+	return func(o *logOptions) {
+		if n > 0 {
+			o.maxAttrs = n
+		}
+	}
+}
+
+// WithAttrCount includes the number of attrs of each record as attrCount, to help find over-instrumented code paths.
+// The count includes the attrs added with Logger.With and the injected fields, but not attrCount itself.
+// A group attr counts as a single attr.
+func WithAttrCount() LogOption {
+	return func(o *logOptions) {
+		o.attrCount = true
+	}
+}
+
+// WithVerbosityFlag includes whether DEBUG records are logged by the handler as debugEnabled,
+// to tell apart records of invocations that ran with more detailed logging. See HandlerLevel.
+func WithVerbosityFlag() LogOption {
+	return func(o *logOptions) {
+		o.verbosity = true
+	}
+}
+
+// WithDeadlineWarning escalates records logged with less than threshold remaining before the deadline
+// of the context to WARN, and includes nearTimeout with the value true in them, so that the last records
+// of invocations about to time out stand out. Records above WARN keep their level.
+func WithDeadlineWarning(threshold time.Duration) LogOption {
+	return func(o *logOptions) {
+		o.nearTimeout = threshold
+	}
+}
+
+// WithPromotedKeys copies the named keys from groups to the top level of JSON records,
+// where CloudWatch Logs Insights discovers them automatically. The nested keys are left in place.
+// Keys already present at the top level are not overwritten. It has no effect with the text format.
+func WithPromotedKeys(keys ...string) LogOption {
+	return func(o *logOptions) {
+		o.promotedKeys = append(o.promotedKeys, keys...)
+	}
+}
+
+// WithFieldToggle makes the inclusion of the field named key conditional on enabled, which is consulted for every record.
+// This allows fields to be switched on and off at runtime, for example based on an environment variable or a flag set by an extension.
+func WithFieldToggle(key string, enabled func() bool) LogOption {
+	return func(o *logOptions) {
+		if o.toggles == nil {
+			o.toggles = map[string]func() bool{}
+		}
+		o.toggles[key] = enabled
 	}
 }
 
@@ -49,28 +340,131 @@ func WithTenantID() LogOption {
 // It reads AWS_LAMBDA_LOG_FORMAT and AWS_LAMBDA_LOG_LEVEL from environment,
 // and injects requestId from Lambda context into each log record.
 //
-// By default, only requestId is injected. Use WithFunctionARN or WithTenantID to include more.
+// By default, only requestId is injected. Use WithFunctionARN, WithTenantID or WithFields to include more.
 // See the package examples for usage.
 func NewLogHandler(opts ...LogOption) slog.Handler {
-	options := &logOptions{}
+	options := &logOptions{messageKey: "message", writer: os.Stdout}
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	level := parseLogLevel()
+	level, jsonFormat := parseLogLevel(), isJSONFormat()
+	if logLevel == "" && options.level != "" {
+		level = parseLevel(options.level)
+	}
+	if logFormat == "" && options.format != "" {
+		jsonFormat = strings.ToUpper(options.format) == "JSON"
+	}
 	handlerOpts := &slog.HandlerOptions{
 		Level:       level,
-		ReplaceAttr: ReplaceAttr,
+		ReplaceAttr: replaceAttrFor(options.messageKey, options.messageAsArray && jsonFormat, options.emptyMessage),
 	}
 
 	var h slog.Handler
-	if logFormat == "JSON" {
-		h = slog.NewJSONHandler(os.Stdout, handlerOpts)
+	var batch *batchWriter
+	var closer io.Closer
+	if options.levelFiles != nil {
+		h = options.levelFiles.router(func(w io.Writer) slog.Handler {
+			return newFormatHandler(w, options, handlerOpts, jsonFormat)
+		})
+		closer = options.levelFiles
+	} else if options.sink != nil {
+		h = newSinkHandler(options.sink, func(buf *sinkBuffer) slog.Handler {
+			return newFormatHandler(buf, options, handlerOpts, jsonFormat)
+		})
+		closer = options.sink
 	} else {
-		h = slog.NewTextHandler(os.Stdout, handlerOpts)
+		w := options.writer
+		if options.batch && jsonFormat {
+			batch = newBatchWriter(w)
+			w = batch
+		}
+		h = newFormatHandler(w, options, handlerOpts, jsonFormat)
+		if c, ok := options.writer.(io.Closer); ok && options.writer != os.Stdout && options.writer != os.Stderr {
+			closer = c
+		}
 	}
+	gzip, _ := options.writer.(*gzipWriter)
+	var aggregator *errorAggregator
+	if options.aggregate {
+		aggregator = newErrorAggregator()
+	}
+	lh := &lambdaHandler{
+		handler:      h,
+		level:        level,
+		requestIDKey: options.requestIDKey,
+		fields:       options.fields,
+		fieldSet:     options.fieldSet,
+		toggles:      options.toggles,
+		batch:        batch,
+		aggregator:   aggregator,
+		suppress:     options.suppress,
+		condition:    options.condition,
+		backoff:      options.backoff,
+		onDrop:       options.onDrop,
+		xray:         options.xray,
+		transforms:   options.transforms,
+		keySanitizer: options.keySanitizer,
+		duplicates:   options.duplicates,
+		attrCount:    options.attrCount,
+		verbosity:    options.verbosity,
+		bufferDepth:  options.bufferDepth,
+		nearTimeout:  options.nearTimeout,
+		location:     options.location,
+		maxAttrs:     options.maxAttrs,
+		gzip:         gzip,
+		closer:       closer,
+		cache:        &atomic.Pointer[cachedAttrs]{},
+	}
+	for _, fn := range options.invokeStart {
+		lh.onInvokeStart(fn)
+	}
+	for _, fn := range options.invokeEnd {
+		lh.onInvokeEnd(fn)
+	}
+	if batch != nil {
+		lh.onInvokeStart(batch.invokeStarted)
+		lh.onInvokeEnd(batch.invokeEnded)
+	}
+	if options.duplicates != nil {
+		lh.onInvokeStart(lh.warnDuplicate)
+	}
+	if aggregator != nil {
+		lh.onInvokeStart(aggregator.invokeStarted)
+		lh.onInvokeEnd(aggregator.invokeEnded)
+	}
+	if options.suppressInit {
+		lh.started = &atomic.Bool{}
+		lh.onInvokeStart(func(context.Context) { lh.started.Store(true) })
+	}
+	if options.heartbeat > 0 {
+		startHeartbeats(lh, options.heartbeat)
+	}
+	if options.diagnostic {
+		lh.logDiagnostic(options, level, jsonFormat)
+	}
+	return lh
+}
 
-	return &lambdaHandler{handler: h, fields: options.fields}
+// newFormatHandler returns the JSON or Text handler writing records to w, applying the configured output options.
+func newFormatHandler(w io.Writer, options *logOptions, handlerOpts *slog.HandlerOptions, jsonFormat bool) slog.Handler {
+	// writers applied to each encoded record, from the last applied to the first
+	if options.checksumKey != nil {
+		w = &checksumWriter{out: w, key: options.checksumKey, json: jsonFormat}
+	}
+	if options.recordSize {
+		w = &recordSizeWriter{out: w, json: jsonFormat}
+	}
+	if len(options.promotedKeys) > 0 && jsonFormat {
+		w = &promotedKeysWriter{out: w, keys: options.promotedKeys}
+	}
+	if options.keyOrder != nil && jsonFormat {
+		w = &keyOrderWriter{out: w, cmp: options.keyOrder, pinned: []string{"timestamp", "level", options.messageKey}}
+	}
+	if jsonFormat {
+		return slog.NewJSONHandler(w, handlerOpts)
+	}
+	return slog.NewTextHandler(w, handlerOpts)
 }
 
 // NewLogger returns a [*slog.Logger] configured for AWS Lambda structured logging.
@@ -79,6 +473,34 @@ func NewLogger(opts ...LogOption) *slog.Logger {
 	return slog.New(NewLogHandler(opts...))
 }
 
+// SaveAndRestoreDefault saves the default slog logger, and returns a function restoring it, for tests that call
+// slog.SetDefault, such as with a logger returned by NewLogger:
+//
+//	t.Cleanup(lambdacontext.SaveAndRestoreDefault())
+//	slog.SetDefault(lambdacontext.NewLogger())
+//
+// The output, flags and prefix of the standard library logger, which slog.SetDefault redirects, are restored too.
+func SaveAndRestoreDefault() func() {
+	logger, w, flags, prefix := slog.Default(), log.Writer(), log.Flags(), log.Prefix()
+	return func() {
+		slog.SetDefault(logger)
+		log.SetOutput(w)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}
+
+// Inject returns a [slog.Handler] that adds the requestId and the given fields to each record
+// before passing it to inner, making an existing handler Lambda-context aware.
+// Key renaming and the other output options of NewLogHandler are left to inner.
+func Inject(inner slog.Handler, fields ...Field) slog.Handler {
+	return &lambdaHandler{
+		handler: inner,
+		fields:  fields,
+		cache:   &atomic.Pointer[cachedAttrs]{},
+	}
+}
+
 // ReplaceAttr maps slog's default keys to AWS Lambda's log format (time->timestamp, msg->message).
 func ReplaceAttr(groups []string, attr slog.Attr) slog.Attr {
 	if len(groups) > 0 {
@@ -94,10 +516,149 @@ func ReplaceAttr(groups []string, attr slog.Attr) slog.Attr {
 	return attr
 }
 
+// replaceAttrFor returns ReplaceAttr with the message emitted under messageKey,
+// additionally splitting messages containing newlines into an array of lines when splitLines is set,
+// and applying emptyMessage to empty messages.
+func replaceAttrFor(messageKey string, splitLines bool, emptyMessage EmptyMessagePolicy) func([]string, slog.Attr) slog.Attr {
+	if messageKey == "message" && !splitLines && emptyMessage == EmptyMessageKeep {
+		return ReplaceAttr
+	}
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) > 0 || attr.Key != slog.MessageKey {
+			return ReplaceAttr(groups, attr)
+		}
+		msg := attr.Value.String()
+		switch {
+		case msg == "" && emptyMessage == EmptyMessageOmit:
+			return slog.Attr{}
+		case msg == "" && emptyMessage == EmptyMessagePlaceholder:
+			attr.Value = slog.StringValue(emptyMessagePlaceholder)
+		case splitLines && strings.Contains(msg, "\n"):
+			attr.Value = slog.AnyValue(strings.Split(msg, "\n"))
+		}
+		attr.Key = messageKey
+		return attr
+	}
+}
+
 // lambdaHandler wraps a slog.Handler to inject Lambda context fields.
 type lambdaHandler struct {
-	handler slog.Handler
-	fields  []field
+	handler      slog.Handler
+	level        slog.Leveler // nil for handlers returned by Inject
+	requestIDKey string       // empty for the default, "requestId"
+	fields       []Field
+	fieldSet     func(context.Context) []Field
+	toggles      map[string]func() bool
+	batch        *batchWriter
+	aggregator   *errorAggregator
+	suppress     []*regexp.Regexp
+	condition    func(context.Context) bool
+	started      *atomic.Bool // nil unless records are dropped until the first invocation starts
+	backoff      *errorBackoff
+	onDrop       func(string, slog.Record)
+	xray         *xrayAnnotator
+	transforms   []attrTransform
+	keySanitizer func(string) string
+	duplicates   *duplicateDetector
+	attrCount    bool
+	verbosity    bool
+	bufferDepth  bool
+	nearTimeout  time.Duration // zero unless records are escalated near the deadline
+	location     *time.Location
+	maxAttrs     int
+	boundAttrs   int // number of attrs added with WithAttrs
+	gzip         *gzipWriter
+	closer       io.Closer
+	hooks        []func() // unregister the invocation hooks of the handler
+	cache        *atomic.Pointer[cachedAttrs]
+}
+
+// cachedAttrs are the cached attrs of a LambdaContext.
+type cachedAttrs struct {
+	lc        *LambdaContext
+	requestID string
+	attrs     []slog.Attr
+}
+
+// Fields returns the keys of the optional fields included in log records, in configuration order.
+func (h *lambdaHandler) Fields() []string {
+	keys := make([]string, 0, len(h.fields))
+	for _, field := range h.fields {
+		keys = append(keys, field.key)
+	}
+	return keys
+}
+
+// HandlerFields returns the keys of the optional fields configured on a handler returned by NewLogHandler.
+// It returns nil for any other handler.
+func HandlerFields(h slog.Handler) []string {
+	if lh, ok := h.(*lambdaHandler); ok {
+		return lh.Fields()
+	}
+	return nil
+}
+
+// HandlerLevel returns the minimum level of the records logged by a handler returned by NewLogHandler
+// or HandlerFromConfig, whether configured by AWS_LAMBDA_LOG_LEVEL or by Config.Level.
+// It allows callers to skip building expensive log arguments. It returns EffectiveLevel for any other handler.
+func HandlerLevel(h slog.Handler) slog.Level {
+	if lh, ok := h.(*lambdaHandler); ok && lh.level != nil {
+		return lh.level.Level()
+	}
+	return EffectiveLevel()
+}
+
+// Flush writes any records buffered by a handler returned by NewLogHandler.
+// It is a no-op for other handlers, and for handlers that do not buffer records.
+func Flush(h slog.Handler) error {
+	lh, ok := h.(*lambdaHandler)
+	if !ok {
+		return nil
+	}
+	if lh.aggregator != nil {
+		if err := lh.aggregator.flushAll(); err != nil {
+			return err
+		}
+	}
+	if lh.batch != nil {
+		if err := lh.batch.flushAll(); err != nil {
+			return err
+		}
+	}
+	if lh.gzip != nil {
+		return lh.gzip.flush()
+	}
+	return nil
+}
+
+// Close flushes a handler returned by NewLogHandler and closes its destination, if it needs closing.
+// It also unregisters the invocation hooks of the handler, so that handlers created repeatedly,
+// such as in tests, should be closed. Stdout and stderr are never closed. It is a no-op for other handlers.
+func Close(h slog.Handler) error {
+	lh, ok := h.(*lambdaHandler)
+	if !ok {
+		return nil
+	}
+	for _, unregister := range lh.hooks {
+		unregister()
+	}
+	if err := Flush(h); err != nil {
+		return err
+	}
+	if lh.closer != nil {
+		return lh.closer.Close()
+	}
+	return nil
+}
+
+// onInvokeStart registers fn with OnInvokeStart until the handler is closed.
+func (h *lambdaHandler) onInvokeStart(fn func(context.Context)) {
+	h.hooks = append(h.hooks, OnInvokeStart(fn))
+}
+
+// onInvokeEnd registers fn with OnInvokeEnd until the handler is closed.
+func (h *lambdaHandler) onInvokeEnd(fn func(context.Context)) {
+	h.hooks = append(h.hooks, OnInvokeEnd(fn))
 }
 
 // Enabled implements slog.Handler.
@@ -107,45 +668,256 @@ func (h *lambdaHandler) Enabled(ctx context.Context, level slog.Level) bool {
 
 // Handle implements slog.Handler.
 func (h *lambdaHandler) Handle(ctx context.Context, r slog.Record) error {
-	if lc, ok := FromContext(ctx); ok {
-		r.AddAttrs(slog.String("requestId", lc.AwsRequestID))
+	for _, re := range h.suppress {
+		if re.MatchString(r.Message) {
+			return h.drop(DropReasonPattern, r)
+		}
+	}
+	if h.condition != nil && r.Level < slog.LevelError && !h.condition(ctx) {
+		return h.drop(DropReasonCondition, r)
+	}
+	if h.started != nil && r.Level < slog.LevelError && !h.started.Load() {
+		return h.drop(DropReasonInit, r)
+	}
+	if h.backoff != nil && r.Level >= slog.LevelError {
+		ok, suppressed := h.backoff.observe(r.Message)
+		if !ok {
+			return h.drop(DropReasonBackoff, r)
+		}
+		if suppressed > 0 {
+			r.AddAttrs(slog.Int("suppressed", suppressed))
+		}
+	}
+	if h.aggregator != nil && r.Level >= slog.LevelError {
+		if lc, ok := FromContext(ctx); ok {
+			held, repeated := h.aggregator.add(ctx, h, lc.AwsRequestID, r)
+			if repeated {
+				return h.drop(DropReasonAggregated, r)
+			}
+			if held {
+				return nil
+			}
+		}
+	}
+	return h.handle(ctx, r)
+}
 
-		for _, field := range h.fields {
-			if v := field.value(lc); v != "" {
-				r.AddAttrs(slog.String(field.key, v))
+// handle adds the Lambda context fields to r and passes it on.
+func (h *lambdaHandler) handle(ctx context.Context, r slog.Record) error {
+	if h.location != nil && !r.Time.IsZero() {
+		r.Time = r.Time.In(h.location)
+	}
+	if len(h.transforms) > 0 {
+		r = transformRecord(h.transforms, r)
+	}
+	if h.keySanitizer != nil {
+		r = sanitizeRecord(h.keySanitizer, r)
+	}
+	lc, ok := FromContext(ctx)
+	if ok {
+		r.AddAttrs(h.lambdaContextAttrs(lc)...)
+	}
+	for _, field := range h.fields {
+		if h.isCached(field) {
+			continue
+		}
+		if enabled, ok := h.toggles[field.key]; ok && !enabled() {
+			continue
+		}
+		if v, ok := field.resolve(ctx, lc); ok {
+			r.AddAttrs(slog.Attr{Key: field.key, Value: v})
+		}
+	}
+	if h.fieldSet != nil {
+		for _, field := range h.fieldSet(ctx) {
+			if enabled, ok := h.toggles[field.key]; ok && !enabled() {
+				continue
+			}
+			if v, ok := field.resolve(ctx, lc); ok {
+				r.AddAttrs(slog.Attr{Key: field.key, Value: v})
+			}
+		}
+	}
+	if h.maxAttrs > 0 {
+		var omitted int
+		if r, omitted = limitAttrs(r, h.maxAttrs, h.isReserved); omitted > 0 {
+			r.AddAttrs(slog.Int("attrsOmitted", omitted))
+		}
+	}
+	if h.verbosity {
+		r.AddAttrs(slog.Bool("debugEnabled", h.handler.Enabled(ctx, slog.LevelDebug)))
+	}
+	if h.nearTimeout > 0 {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < h.nearTimeout {
+			if r.Level < slog.LevelWarn {
+				r.Level = slog.LevelWarn
 			}
+			r.AddAttrs(slog.Bool("nearTimeout", true))
 		}
 	}
+	if h.attrCount {
+		r.AddAttrs(slog.Int("attrCount", h.boundAttrs+r.NumAttrs()))
+	}
+	if h.xray != nil {
+		h.xray.annotate(ctx, r)
+	}
+	var requestID string
+	if ok {
+		requestID = lc.AwsRequestID
+	}
+	if h.bufferDepth {
+		if attr, ok := h.bufferAttr(requestID); ok {
+			r.AddAttrs(attr)
+		}
+	}
+	return h.emit(ctx, requestID, r)
+}
+
+// emit passes r to the wrapped handler, buffering it with the records of its invocation when batching.
+func (h *lambdaHandler) emit(ctx context.Context, requestID string, r slog.Record) error {
+	if h.batch != nil {
+		return h.batch.handle(requestID, func() error { return h.handler.Handle(ctx, r) })
+	}
 	return h.handler.Handle(ctx, r)
 }
 
+// lambdaContextAttrs returns the requestId attr and the attrs of the fields derived only from lc.
+// The attrs of the last seen LambdaContext are cached, as consecutive records usually belong to the same invocation.
+func (h *lambdaHandler) lambdaContextAttrs(lc *LambdaContext) []slog.Attr {
+	if h.cache != nil {
+		if cached := h.cache.Load(); cached != nil && cached.lc == lc && cached.requestID == lc.AwsRequestID {
+			return cached.attrs
+		}
+	}
+	attrs := []slog.Attr{h.requestIDAttr(lc.AwsRequestID)}
+	for _, field := range h.fields {
+		if !h.isCached(field) {
+			continue
+		}
+		if v := field.value(lc); v != "" {
+			attrs = append(attrs, slog.String(field.key, v))
+		}
+	}
+	if h.cache != nil {
+		h.cache.Store(&cachedAttrs{lc: lc, requestID: lc.AwsRequestID, attrs: attrs})
+	}
+	return attrs
+}
+
+// requestIDAttr returns the attr of the request ID, under the configured key.
+func (h *lambdaHandler) requestIDAttr(requestID string) slog.Attr {
+	if h.requestIDKey == "" {
+		return slog.String("requestId", requestID)
+	}
+	return slog.String(h.requestIDKey, requestID)
+}
+
+// isReserved reports whether key is the key of the request ID or of a configured field.
+func (h *lambdaHandler) isReserved(key string) bool {
+	if key == h.requestIDAttr("").Key {
+		return true
+	}
+	for _, field := range h.fields {
+		if field.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// isCached reports whether the attr of field is cached by lambdaContextAttrs,
+// which is the case for fields derived only from the LambdaContext and that are not toggled.
+func (h *lambdaHandler) isCached(field Field) bool {
+	if field.attr != nil {
+		return false
+	}
+	_, toggled := h.toggles[field.key]
+	return !toggled
+}
+
 // WithAttrs implements slog.Handler.
 func (h *lambdaHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &lambdaHandler{
-		handler: h.handler.WithAttrs(attrs),
-		fields:  h.fields,
+	if len(h.transforms) > 0 {
+		transformed := make([]slog.Attr, len(attrs))
+		for i, attr := range attrs {
+			transformed[i] = transformAttr(h.transforms, attr)
+		}
+		attrs = transformed
 	}
+	if h.keySanitizer != nil {
+		sanitized := make([]slog.Attr, len(attrs))
+		for i, attr := range attrs {
+			sanitized[i] = sanitizeAttr(h.keySanitizer, attr)
+		}
+		attrs = sanitized
+	}
+	clone := h.withHandler(h.handler.WithAttrs(attrs))
+	clone.boundAttrs += len(attrs)
+	return clone
 }
 
 // WithGroup implements slog.Handler.
 func (h *lambdaHandler) WithGroup(name string) slog.Handler {
-	return &lambdaHandler{
-		handler: h.handler.WithGroup(name),
-		fields:  h.fields,
+	if h.keySanitizer != nil && name != "" {
+		name = h.keySanitizer(name)
 	}
+	return h.withHandler(h.handler.WithGroup(name))
+}
+
+// withHandler returns a copy of h wrapping handler, sharing h's configuration.
+func (h *lambdaHandler) withHandler(handler slog.Handler) *lambdaHandler {
+	clone := *h
+	clone.handler = handler
+	return &clone
+}
+
+// isJSONFormat reports whether logFormat selects JSON output. Any other value selects text.
+func isJSONFormat() bool {
+	return strings.ToUpper(logFormat) == "JSON"
+}
+
+// EffectiveLevel returns the level configured by AWS_LAMBDA_LOG_LEVEL, INFO by default, which is the minimum level
+// of the records logged by handlers returned by NewLogHandler unless a Config sets another level while the variable
+// is unset. Use HandlerLevel for the level of a given handler.
+func EffectiveLevel() slog.Level {
+	return parseLogLevel()
 }
 
+// parseLogLevel returns the level of AWS_LAMBDA_LOG_LEVEL, see parseLevel.
 func parseLogLevel() slog.Level {
-	switch logLevel {
+	return parseLevel(logLevel)
+}
+
+// parseLevel returns the level of s, either a Lambda log level name or a numeric slog.Level such as -4 for DEBUG.
+// It defaults to INFO.
+func parseLevel(s string) slog.Level {
+	if n, err := strconv.Atoi(s); err == nil {
+		return slog.Level(n)
+	}
+	if level, ok := SlogLevel(s); ok {
+		return level
+	}
+	return slog.LevelInfo
+}
+
+// SlogLevel returns the [slog.Level] of a Lambda application log level name, as used by AWS_LAMBDA_LOG_LEVEL:
+// TRACE, DEBUG, INFO, WARN, ERROR or FATAL. TRACE and FATAL, which have no slog equivalent,
+// map to 4 below DEBUG and 4 above ERROR. It reports false for any other name, including lowercase names.
+func SlogLevel(name string) (slog.Level, bool) {
+	switch name {
+	case "TRACE":
+		return slog.LevelDebug - 4, true
 	case "DEBUG":
-		return slog.LevelDebug
+		return slog.LevelDebug, true
 	case "INFO":
-		return slog.LevelInfo
+		return slog.LevelInfo, true
 	case "WARN":
-		return slog.LevelWarn
+		return slog.LevelWarn, true
 	case "ERROR":
-		return slog.LevelError
+		return slog.LevelError, true
+	case "FATAL":
+		return slog.LevelError + 4, true
 	default:
-		return slog.LevelInfo
+		return 0, false
 	}
 }
@@ -0,0 +1,118 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// errorAggregator holds the ERROR records of each active invocation, keyed by request ID, counting identical records.
+type errorAggregator struct {
+	lock    sync.Mutex
+	active  map[string]bool // request IDs of the invocations started and not yet ended
+	pending map[string][]*aggregatedError
+}
+
+// aggregatedError is an ERROR record held until the end of its invocation, with the number of times it was logged.
+type aggregatedError struct {
+	hash    uint64
+	ctx     context.Context
+	handler *lambdaHandler
+	record  slog.Record
+	count   int
+}
+
+// WithErrorAggregation holds the ERROR records of each invocation until it ends, and then writes each distinct
+// record once, with the number of times it was logged as count, instead of repeating identical records.
+// Records are identical when their message and attrs are, see RecordHash; attrs added with slog.Logger.With
+// are not compared. Records logged outside of an invocation, as signaled by the invocation hooks called by
+// the lambda package, are written immediately, such as those of goroutines still logging after their invocation
+// ended, or of functions invoked through the deprecated RPC mode. Use Flush to write held records early.
+func WithErrorAggregation() LogOption {
+	return func(o *logOptions) {
+		o.aggregate = true
+	}
+}
+
+// newErrorAggregator returns an aggregator, writing the records of each invocation when invokeEnded is called.
+func newErrorAggregator() *errorAggregator {
+	return &errorAggregator{active: map[string]bool{}, pending: map[string][]*aggregatedError{}}
+}
+
+// invokeStarted starts holding the records of the invocation of ctx. Records still held for its request ID,
+// should the end of a previous invocation with the same ID have been missed, are written first.
+func (a *errorAggregator) invokeStarted(ctx context.Context) {
+	lc, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	a.lock.Lock()
+	stale := a.pending[lc.AwsRequestID]
+	delete(a.pending, lc.AwsRequestID)
+	a.active[lc.AwsRequestID] = true
+	a.lock.Unlock()
+	_ = writeAggregated(stale)
+}
+
+// invokeEnded writes the records held for the invocation of ctx, and stops holding its records.
+func (a *errorAggregator) invokeEnded(ctx context.Context) {
+	lc, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	a.lock.Lock()
+	held := a.pending[lc.AwsRequestID]
+	delete(a.pending, lc.AwsRequestID)
+	delete(a.active, lc.AwsRequestID)
+	a.lock.Unlock()
+	_ = writeAggregated(held)
+}
+
+// add holds r until the end of the invocation with requestID, to be handled by h with ctx.
+// It reports whether r was held, and whether it was counted as a repeat of a held record instead.
+// Records of invocations that are not active are not held, and should be written immediately.
+func (a *errorAggregator) add(ctx context.Context, h *lambdaHandler, requestID string, r slog.Record) (held, repeated bool) {
+	hash := RecordHash(r)
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if !a.active[requestID] {
+		return false, false
+	}
+	for _, e := range a.pending[requestID] {
+		if e.hash == hash {
+			e.count++
+			return true, true
+		}
+	}
+	a.pending[requestID] = append(a.pending[requestID], &aggregatedError{hash: hash, ctx: ctx, handler: h, record: r.Clone(), count: 1})
+	return true, false
+}
+
+// flushAll writes the records held for every invocation.
+func (a *errorAggregator) flushAll() error {
+	a.lock.Lock()
+	var held []*aggregatedError
+	for requestID, records := range a.pending {
+		held = append(held, records...)
+		delete(a.pending, requestID)
+	}
+	a.lock.Unlock()
+	return writeAggregated(held)
+}
+
+// writeAggregated passes each held record, with its count, to the handler that held it.
+func writeAggregated(held []*aggregatedError) error {
+	var errs []error
+	for _, e := range held {
+		r := e.record.Clone()
+		r.AddAttrs(slog.Int("count", e.count))
+		errs = append(errs, e.handler.handle(e.ctx, r))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,122 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithErrorAggregation(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	handler := NewLogHandler(WithErrorAggregation(), withWriter(&buf))
+	defer Close(handler)
+	logger := slog.New(handler)
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	InvokeStarted(ctx)
+
+	for i := 0; i < 3; i++ {
+		logger.ErrorContext(ctx, "write failed", "table", "orders")
+	}
+	logger.ErrorContext(ctx, "write failed", "table", "customers")
+	logger.InfoContext(ctx, "done")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "done", logOutput["message"], "errors should be held until the invocation ends")
+
+	buf.Reset()
+	InvokeEnded(ctx)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var orders, customers map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &orders))
+	require.NoError(t, json.Unmarshal(lines[1], &customers))
+
+	assert.Equal(t, "write failed", orders["message"])
+	assert.Equal(t, "orders", orders["table"])
+	assert.Equal(t, float64(3), orders["count"])
+	assert.Equal(t, "test-request", orders["requestId"])
+	assert.Equal(t, "customers", customers["table"])
+	assert.Equal(t, float64(1), customers["count"])
+
+	// the records of an ended invocation are not written again
+	buf.Reset()
+	InvokeEnded(ctx)
+	assert.Empty(t, buf.String())
+
+	// records logged after the invocation ended are written immediately
+	logger.ErrorContext(ctx, "late failure")
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "late failure", logOutput["message"])
+	assert.NotContains(t, logOutput, "count")
+}
+
+func TestWithErrorAggregation_InvocationNotStarted(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	// such as when the lambda package does not call the invocation hooks
+	var buf bytes.Buffer
+	handler := NewLogHandler(WithErrorAggregation(), withWriter(&buf))
+	defer Close(handler)
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	slog.New(handler).ErrorContext(ctx, "write failed")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "write failed", logOutput["message"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+}
+
+func TestWithErrorAggregation_CloseUnregistersHooks(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	registeredStart, registeredEnd := len(invokeStartHooks), len(invokeEndHooks)
+	handler := NewLogHandler(WithErrorAggregation(), withWriter(&bytes.Buffer{}))
+	require.NoError(t, Close(handler))
+	assert.Len(t, invokeStartHooks, registeredStart)
+	assert.Len(t, invokeEndHooks, registeredEnd)
+}
+
+func TestWithErrorAggregation_OutsideInvocation(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	NewLogger(WithErrorAggregation(), withWriter(&buf)).Error("init failed")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "init failed", logOutput["message"])
+	assert.NotContains(t, logOutput, "count")
+}
+
+func TestWithErrorAggregation_Flush(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	handler := NewLogHandler(WithErrorAggregation(), withWriter(&buf))
+	defer Close(handler)
+	logger := slog.New(handler)
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	InvokeStarted(ctx)
+	logger.ErrorContext(ctx, "write failed")
+	logger.ErrorContext(ctx, "write failed")
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, Flush(logger.Handler()))
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, float64(2), logOutput["count"])
+}
@@ -0,0 +1,197 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"log"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// attrTransform rewrites an attr before it is logged. Group attrs are transformed member by member.
+type attrTransform func(slog.Attr) slog.Attr
+
+// transformAttr applies transforms to attr and, recursively, to the members of groups.
+func transformAttr(transforms []attrTransform, attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		transformed := make([]slog.Attr, len(members))
+		for i, member := range members {
+			transformed[i] = transformAttr(transforms, member)
+		}
+		attr.Value = slog.GroupValue(transformed...)
+		return attr
+	}
+	for _, transform := range transforms {
+		attr = transform(attr)
+	}
+	return attr
+}
+
+// transformRecord returns a copy of r with transforms applied to its attrs.
+func transformRecord(transforms []attrTransform, r slog.Record) slog.Record {
+	transformed := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		transformed.AddAttrs(transformAttr(transforms, attr))
+		return true
+	})
+	return transformed
+}
+
+// limitAttrs returns r with only the first max of its attrs that are not reserved, and the number of attrs omitted.
+func limitAttrs(r slog.Record, max int, reserved func(key string) bool) (slog.Record, int) {
+	limited := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	kept, omitted := 0, 0
+	r.Attrs(func(attr slog.Attr) bool {
+		switch {
+		case reserved(attr.Key):
+			limited.AddAttrs(attr)
+		case kept < max:
+			limited.AddAttrs(attr)
+			kept++
+		default:
+			omitted++
+		}
+		return true
+	})
+	return limited, omitted
+}
+
+// WithValueFormatter rewrites attr values, including those within groups, with fn, to control how values
+// of specific types are rendered, such as times in a custom layout. Values for which fn returns false are unchanged.
+// Group values are not passed to fn, only their members.
+func WithValueFormatter(fn func(slog.Value) (slog.Value, bool)) LogOption {
+	return func(o *logOptions) {
+		o.transforms = append(o.transforms, func(attr slog.Attr) slog.Attr {
+			if v, ok := fn(attr.Value); ok {
+				attr.Value = v
+			}
+			return attr
+		})
+	}
+}
+
+// WithKeySanitizer rewrites the keys of attrs, including the keys of groups and their members, with fn,
+// such as to replace characters rejected by a log backend:
+//
+//	lambdacontext.WithKeySanitizer(func(key string) string { return strings.ReplaceAll(key, ".", "_") })
+//
+// The timestamp, level and message keys, the requestId and the configured fields are not rewritten;
+// see WithMessageKey and WithRequestIDKey to rename them.
+func WithKeySanitizer(fn func(string) string) LogOption {
+	return func(o *logOptions) {
+		o.keySanitizer = fn
+	}
+}
+
+// sanitizeAttr rewrites the key of attr with fn and, recursively, the keys of the members of groups.
+func sanitizeAttr(fn func(string) string, attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Key != "" {
+		attr.Key = fn(attr.Key)
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		sanitized := make([]slog.Attr, len(members))
+		for i, member := range members {
+			sanitized[i] = sanitizeAttr(fn, member)
+		}
+		attr.Value = slog.GroupValue(sanitized...)
+	}
+	return attr
+}
+
+// sanitizeRecord returns a copy of r with the keys of its attrs rewritten by fn.
+func sanitizeRecord(fn func(string) string, r slog.Record) slog.Record {
+	sanitized := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		sanitized.AddAttrs(sanitizeAttr(fn, attr))
+		return true
+	})
+	return sanitized
+}
+
+// UnitSuffixes are the key suffixes recognized by WithUnitSuffixCheck as naming the unit of a numeric value.
+var UnitSuffixes = []string{
+	"Ns", "Us", "Ms", "Seconds", "Minutes", "Hours", "Days",
+	"Bytes", "Kb", "Mb", "Gb",
+	"Count", "Percent", "Ratio",
+}
+
+// WithUnitSuffixCheck warns, with the standard library logger, about numeric attrs whose key does not end
+// with one of UnitSuffixes, such as duration instead of durationMs, to keep dashboards unambiguous.
+// Each key is warned about once. The check is a development aid: it does not change records,
+// and is a no-op when running in the Lambda execution environment.
+func WithUnitSuffixCheck() LogOption {
+	return func(o *logOptions) {
+		if initType != "" {
+			return
+		}
+		var warned sync.Map
+		o.transforms = append(o.transforms, func(attr slog.Attr) slog.Attr {
+			if isNumeric(attr.Value) && !hasUnitSuffix(attr.Key) {
+				if _, loaded := warned.LoadOrStore(attr.Key, true); !loaded {
+					log.Printf("WARNING! numeric log attr %q has no unit suffix, such as %q", attr.Key, attr.Key+"Ms")
+				}
+			}
+			return attr
+		})
+	}
+}
+
+// isNumeric reports whether v is a number, including durations.
+func isNumeric(v slog.Value) bool {
+	switch v.Kind() {
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindDuration:
+		return true
+	}
+	return false
+}
+
+// hasUnitSuffix reports whether key ends with one of UnitSuffixes, or is one of them, such as count.
+func hasUnitSuffix(key string) bool {
+	for _, suffix := range UnitSuffixes {
+		if strings.HasSuffix(key, suffix) || strings.EqualFold(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// piiMask replaces values matched by the PII patterns.
+const piiMask = "[MASKED]"
+
+// DefaultPIIPatterns are the patterns masked by WithPIIMasking when no patterns are given:
+// email addresses, payment card numbers and US social security numbers.
+var DefaultPIIPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+	regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+}
+
+// WithPIIMasking masks the parts of string attr values, including those within groups, that match any of patterns,
+// regardless of the attr's key. DefaultPIIPatterns is used when no patterns are given.
+// Matching every string value against several patterns is costly, so masking is opt-in.
+func WithPIIMasking(patterns ...*regexp.Regexp) LogOption {
+	if len(patterns) == 0 {
+		patterns = DefaultPIIPatterns
+	}
+	return func(o *logOptions) {
+		o.transforms = append(o.transforms, func(attr slog.Attr) slog.Attr {
+			if attr.Value.Kind() != slog.KindString {
+				return attr
+			}
+			masked := attr.Value.String()
+			for _, re := range patterns {
+				masked = re.ReplaceAllString(masked, piiMask)
+			}
+			return slog.String(attr.Key, masked)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPIIMasking(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithPIIMasking(), withWriter(&buf)).With("contact", "reach me at jane.doe@example.com")
+	logger.Info("payment received",
+		slog.Group("payment", slog.String("card", "4111 1111 1111 1111"), slog.Int("amount", 42)),
+		slog.String("ssn", "123-45-6789"),
+		slog.String("orderId", "order-123"),
+	)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+
+	assert.Equal(t, "reach me at [MASKED]", logOutput["contact"])
+	assert.Equal(t, "[MASKED]", logOutput["ssn"])
+	assert.Equal(t, "order-123", logOutput["orderId"])
+	payment := logOutput["payment"].(map[string]interface{})
+	assert.Equal(t, "[MASKED]", payment["card"])
+	assert.Equal(t, float64(42), payment["amount"])
+}
+
+func TestWithPIIMasking_CustomPatterns(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	NewLogger(WithPIIMasking(regexp.MustCompile(`acct-\d+`)), withWriter(&buf)).Info("msg",
+		"account", "acct-1234",
+		"email", "jane.doe@example.com",
+	)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "[MASKED]", logOutput["account"])
+	assert.Equal(t, "jane.doe@example.com", logOutput["email"])
+}
+
+func TestWithMaxAttrs(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithMaxAttrs(2), WithFields(FieldRetryAttempt()), withWriter(&buf))
+	ctx := WithRetryAttempt(NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"}), 1)
+	logger.InfoContext(ctx, "many attrs", "a", 1, "b", 2, "c", 3, "d", 4, "e", 5)
+	logger.InfoContext(ctx, "few attrs", "a", 1)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var many, few map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &many))
+	require.NoError(t, json.Unmarshal(lines[1], &few))
+
+	assert.Equal(t, float64(1), many["a"])
+	assert.Equal(t, float64(2), many["b"])
+	assert.NotContains(t, many, "c")
+	assert.NotContains(t, many, "e")
+	assert.Equal(t, float64(3), many["attrsOmitted"])
+	assert.Equal(t, "test-request", many["requestId"])
+	assert.Equal(t, float64(1), many["retryAttempt"])
+
+	assert.NotContains(t, few, "attrsOmitted")
+}
+
+func TestWithValueFormatter(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithValueFormatter(func(v slog.Value) (slog.Value, bool) {
+		if v.Kind() != slog.KindTime {
+			return v, false
+		}
+		return slog.StringValue(v.Time().Format("2006-01-02")), true
+	}), withWriter(&buf))
+
+	shipped := time.Date(2026, time.March, 14, 15, 9, 26, 0, time.UTC)
+	logger.With("created", shipped).Info("order shipped", "shipped", shipped, "count", 2, slog.Group("order", "due", shipped))
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "2026-03-14", logOutput["shipped"])
+	assert.Equal(t, "2026-03-14", logOutput["created"])
+	assert.Equal(t, map[string]interface{}{"due": "2026-03-14"}, logOutput["order"])
+	assert.Equal(t, float64(2), logOutput["count"])
+	assert.NotEqual(t, "2026-03-14", logOutput["timestamp"])
+}
+
+func TestWithUnitSuffixCheck(t *testing.T) {
+	withLogFormat(t, "JSON")
+	withInitType(t, "")
+
+	var warnings bytes.Buffer
+	log.SetOutput(&warnings)
+	defer log.SetOutput(os.Stderr)
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithUnitSuffixCheck(), withWriter(&buf))
+	logger.Info("request done", "duration", 12, "sizeBytes", 512, "count", 3, "table", "orders")
+	logger.Info("request done", "duration", 15)
+
+	assert.Equal(t, 1, strings.Count(warnings.String(), "WARNING!"))
+	assert.Contains(t, warnings.String(), `"duration"`)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(bytes.Split(buf.Bytes(), []byte("\n"))[0], &logOutput))
+	assert.Equal(t, float64(12), logOutput["duration"])
+}
+
+func TestWithUnitSuffixCheck_NoopInLambda(t *testing.T) {
+	withInitType(t, "on-demand")
+
+	options := &logOptions{}
+	WithUnitSuffixCheck()(options)
+	assert.Empty(t, options.transforms)
+}
+
+func TestWithKeySanitizer(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithKeySanitizer(func(key string) string {
+		return strings.ReplaceAll(key, ".", "_")
+	}), WithFunctionARN(), withWriter(&buf))
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request", InvokedFunctionArn: "arn:aws:lambda:us-east-1:123456789012:function:orders"})
+
+	logger.With("x.y", 1).WithGroup("g.h").InfoContext(ctx, "sanitized", "a.b", "value", slog.Group("c.d", "e.f", 2))
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, float64(1), logOutput["x_y"])
+	require.Contains(t, logOutput, "g_h")
+	group := logOutput["g_h"].(map[string]interface{})
+	assert.Equal(t, "value", group["a_b"])
+	assert.Equal(t, map[string]interface{}{"e_f": float64(2)}, group["c_d"])
+	assert.Equal(t, "test-request", group["requestId"])
+	assert.Contains(t, group, "functionArn")
+	assert.NotContains(t, buf.String(), "a.b")
+}
@@ -0,0 +1,45 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import "sync"
+
+// maxErrorBackoffMessages bounds the number of messages errorBackoff counts, so that messages embedding
+// variable values cannot grow it without bound.
+const maxErrorBackoffMessages = 1000
+
+// errorBackoff counts the occurrences of ERROR records by message, to log them with exponential backoff.
+type errorBackoff struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// WithErrorBackoff throttles repeated ERROR records, such as those of a persistently failing dependency.
+// For each message, only the 1st, 2nd, 4th, 8th and so on occurrences are logged, and each logged record
+// includes the number of occurrences suppressed since the previous one as suppressed, when any were.
+// Counts are kept for the lifetime of the handler, up to 1000 distinct messages, after which they are reset,
+// so messages should not embed variable values.
+func WithErrorBackoff() LogOption {
+	return func(o *logOptions) {
+		o.backoff = &errorBackoff{counts: map[string]int{}}
+	}
+}
+
+// observe counts an occurrence of msg, and reports whether it should be logged,
+// with the number of occurrences suppressed since the previous one that was.
+func (b *errorBackoff) observe(msg string) (bool, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.counts[msg]; !ok && len(b.counts) >= maxErrorBackoffMessages {
+		clear(b.counts)
+	}
+	b.counts[msg]++
+	n := b.counts[msg]
+	if n&(n-1) != 0 {
+		return false, 0
+	}
+	return true, n - n/2 - 1
+}
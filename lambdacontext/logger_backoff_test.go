@@ -0,0 +1,56 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithErrorBackoff(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithErrorBackoff(), withWriter(&buf))
+	for i := 1; i <= 10; i++ {
+		logger.Error("dependency unavailable", "occurrence", i)
+		logger.Warn("retrying", "occurrence", i)
+	}
+	logger.Error("other failure")
+
+	var errors, warnings []interface{}
+	var suppressed []interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		switch logOutput["message"] {
+		case "dependency unavailable":
+			errors = append(errors, logOutput["occurrence"])
+			suppressed = append(suppressed, logOutput["suppressed"])
+		case "retrying":
+			warnings = append(warnings, logOutput["occurrence"])
+		}
+	}
+	assert.Equal(t, []interface{}{float64(1), float64(2), float64(4), float64(8)}, errors)
+	assert.Equal(t, []interface{}{nil, nil, float64(1), float64(3)}, suppressed)
+	assert.Len(t, warnings, 10, "records below ERROR are not throttled")
+	assert.Contains(t, buf.String(), "other failure")
+}
+
+func TestWithErrorBackoff_Bounded(t *testing.T) {
+	backoff := &errorBackoff{counts: map[string]int{}}
+	for i := 0; i < 3*maxErrorBackoffMessages; i++ {
+		ok, _ := backoff.observe("order " + strconv.Itoa(i) + " failed")
+		assert.True(t, ok)
+		assert.LessOrEqual(t, len(backoff.counts), maxErrorBackoffMessages)
+	}
+	assert.NotContains(t, backoff.counts, "order 0 failed", "counts are reset once the bound is reached")
+}
@@ -0,0 +1,104 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// batchWriter buffers encoded JSON records per invocation, keyed by request ID,
+// and writes each invocation's records as a single JSON array.
+type batchWriter struct {
+	// handleLock is held while a record is encoded, so that Write knows which invocation it belongs to.
+	handleLock sync.Mutex
+	current    string
+
+	lock    sync.Mutex
+	out     io.Writer
+	active  map[string]bool // request IDs of the invocations started and not yet ended
+	pending map[string][][]byte
+}
+
+func newBatchWriter(out io.Writer) *batchWriter {
+	return &batchWriter{out: out, active: map[string]bool{}, pending: map[string][][]byte{}}
+}
+
+// invokeStarted starts buffering the records of the invocation of ctx.
+func (b *batchWriter) invokeStarted(ctx context.Context) {
+	if lc, ok := FromContext(ctx); ok {
+		b.lock.Lock()
+		b.active[lc.AwsRequestID] = true
+		b.lock.Unlock()
+	}
+}
+
+// invokeEnded writes the records buffered for the invocation of ctx, and stops buffering them.
+func (b *batchWriter) invokeEnded(ctx context.Context) {
+	if lc, ok := FromContext(ctx); ok {
+		_ = b.flush(lc.AwsRequestID)
+	}
+}
+
+// handle calls encode, buffering everything it writes under requestID.
+// Records without a requestID, or of an invocation that is not active, are written immediately.
+func (b *batchWriter) handle(requestID string, encode func() error) error {
+	b.handleLock.Lock()
+	defer b.handleLock.Unlock()
+	b.current = requestID
+	defer func() { b.current = "" }()
+	return encode()
+}
+
+// Write implements io.Writer. It is only called by the JSON handler while handle holds handleLock.
+func (b *batchWriter) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.active[b.current] {
+		return b.out.Write(p)
+	}
+	record := bytes.TrimRight(p, "\n")
+	b.pending[b.current] = append(b.pending[b.current], append([]byte(nil), record...))
+	return len(p), nil
+}
+
+// flush writes the records buffered for requestID, and stops buffering them.
+func (b *batchWriter) flush(requestID string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	records := b.pending[requestID]
+	delete(b.pending, requestID)
+	delete(b.active, requestID)
+	return b.writeArray(records)
+}
+
+// flushAll writes the records buffered for every invocation.
+func (b *batchWriter) flushAll() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	var firstErr error
+	for requestID, records := range b.pending {
+		delete(b.pending, requestID)
+		if err := b.writeArray(records); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (b *batchWriter) writeArray(records [][]byte) error {
+	if len(records) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	buf.Write(bytes.Join(records, []byte{','}))
+	buf.WriteString("]\n")
+	_, err := b.out.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,101 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBatchPerInvocation(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithBatchPerInvocation(), withWriter(&buf))
+	defer Close(logger.Handler())
+
+	ctx1 := NewContext(context.Background(), &LambdaContext{AwsRequestID: "request-aaa"})
+	ctx2 := NewContext(context.Background(), &LambdaContext{AwsRequestID: "request-bbb"})
+	InvokeStarted(ctx1)
+	InvokeStarted(ctx2)
+	defer InvokeEnded(ctx2)
+
+	logger.InfoContext(ctx1, "message 1")
+	logger.InfoContext(ctx2, "message 2")
+	logger.InfoContext(ctx1, "message 3")
+	assert.Empty(t, buf.String(), "records should be buffered until the invocation ends")
+
+	InvokeEnded(ctx1)
+
+	var records []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &records))
+	require.Len(t, records, 2)
+	assert.Equal(t, "message 1", records[0]["message"])
+	assert.Equal(t, "message 3", records[1]["message"])
+	assert.Equal(t, "request-aaa", records[0]["requestId"])
+
+	// records logged after the invocation ended, such as by goroutines, are written immediately
+	buf.Reset()
+	logger.InfoContext(ctx1, "late message")
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "late message", logOutput["message"])
+	assert.NotContains(t, logger.Handler().(*lambdaHandler).batch.pending, "request-aaa")
+
+	buf.Reset()
+
+	require.NoError(t, Flush(logger.Handler()))
+	records = nil
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, "request-bbb", records[0]["requestId"])
+}
+
+func TestWithBatchPerInvocation_OutsideInvocation(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	NewLogger(WithBatchPerInvocation(), withWriter(&buf)).Info("init message")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "init message", logOutput["message"])
+}
+
+func TestWithBatchPerInvocation_InvocationNotStarted(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithBatchPerInvocation(), withWriter(&buf))
+	defer Close(logger.Handler())
+
+	// invocations through the RPC path do not call InvokeStarted
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "request-aaa"})
+	logger.InfoContext(ctx, "message")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "message", logOutput["message"])
+	assert.Equal(t, "request-aaa", logOutput["requestId"])
+}
+
+func TestWithBatchPerInvocation_CloseUnregistersHooks(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	started, ended := len(invokeStartHooks), len(invokeEndHooks)
+	handler := NewLogHandler(WithBatchPerInvocation(), withWriter(&bytes.Buffer{}))
+	assert.Len(t, invokeStartHooks, started+1)
+	assert.Len(t, invokeEndHooks, ended+1)
+
+	require.NoError(t, Close(handler))
+	assert.Len(t, invokeStartHooks, started)
+	assert.Len(t, invokeEndHooks, ended)
+}
@@ -0,0 +1,49 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import "log/slog"
+
+// WithBufferDepth includes the depth of the handler's buffer when each record is logged as a buffer group,
+// to observe backpressure and size buffers: the number of records buffered before the record as records,
+// and, for WithGzip, the number of buffered bytes as bytes and the size at which a block is written as
+// capacityBytes. With WithBatchPerInvocation, records counts the buffered records of the record's invocation,
+// which are unbounded. Nothing is emitted for handlers that do not buffer records.
+func WithBufferDepth() LogOption {
+	return func(o *logOptions) {
+		o.bufferDepth = true
+	}
+}
+
+// bufferAttr returns the buffer group of a record of the invocation with requestID.
+func (h *lambdaHandler) bufferAttr(requestID string) (slog.Attr, bool) {
+	switch {
+	case h.batch != nil && requestID != "":
+		return slog.Group("buffer", slog.Int("records", h.batch.depth(requestID))), true
+	case h.gzip != nil:
+		records, bytes := h.gzip.depth()
+		return slog.Group("buffer",
+			slog.Int("records", records),
+			slog.Int("bytes", bytes),
+			slog.Int("capacityBytes", gzipBlockSize)), true
+	default:
+		return slog.Attr{}, false
+	}
+}
+
+// depth returns the number of records buffered for requestID.
+func (b *batchWriter) depth(requestID string) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return len(b.pending[requestID])
+}
+
+// depth returns the number of records and bytes buffered for the next block.
+func (w *gzipWriter) depth() (records, bytes int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.records, w.buf.Len()
+}
@@ -0,0 +1,67 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBufferDepth(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithBatchPerInvocation(), WithBufferDepth(), withWriter(&buf))
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	InvokeStarted(ctx)
+	logger.InfoContext(ctx, "first")
+	logger.InfoContext(ctx, "second")
+	logger.InfoContext(ctx, "third")
+	InvokeEnded(ctx)
+
+	var records []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &records))
+	require.Len(t, records, 3)
+	for i, record := range records {
+		assert.Equal(t, map[string]interface{}{"records": float64(i)}, record["buffer"])
+	}
+}
+
+func TestWithBufferDepth_Gzip(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	handler := NewLogHandler(WithGzip(&bytes.Buffer{}), WithBufferDepth())
+	slog.New(handler).Info("first")
+
+	attr, ok := handler.(*lambdaHandler).bufferAttr("")
+	require.True(t, ok)
+	group := attr.Value.Group()
+	require.Len(t, group, 3)
+	assert.Equal(t, int64(1), group[0].Value.Int64())
+	assert.Greater(t, group[1].Value.Int64(), int64(0))
+	assert.Equal(t, int64(gzipBlockSize), group[2].Value.Int64())
+
+	require.NoError(t, Flush(handler))
+	attr, _ = handler.(*lambdaHandler).bufferAttr("")
+	assert.Equal(t, int64(0), attr.Value.Group()[0].Value.Int64())
+}
+
+func TestWithBufferDepth_Unbuffered(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	NewLogger(WithBufferDepth(), withWriter(&buf)).Info("unbuffered")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.NotContains(t, logOutput, "buffer")
+}
@@ -0,0 +1,116 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Config is the logging configuration of a handler, as loaded by LoadConfig from a JSON file such as:
+//
+//	{"level": "DEBUG", "format": "JSON", "fields": ["functionArn", "retryAttempt"], "output": "logs/app.log"}
+type Config struct {
+	// Level is a Lambda log level name, such as DEBUG, or a numeric slog.Level.
+	// AWS_LAMBDA_LOG_LEVEL takes precedence when set.
+	Level string `json:"level"`
+	// Format is Text or JSON. AWS_LAMBDA_LOG_FORMAT takes precedence when set.
+	Format string `json:"format"`
+	// Fields are the keys of the fields to include: functionArn, tenantId, qualifier, retryAttempt,
+	// eventSourceArn, eventType, parentRequestId, route, eventLagMs, remainingPercent, initType,
+	// snapStartRestored, ephemeralStorageMb, tmpFreeBytes, service, color, processId, uptimeSeconds,
+	// recordId, gid or inflight.
+	Fields []string `json:"fields"`
+	// Output is stdout, the default, stderr, or the path of a file to append records to.
+	Output string `json:"output"`
+}
+
+// configFields are the fields that can be included by a Config, by key.
+var configFields = map[string]func() LogOption{
+	"functionArn":        WithFunctionARN,
+	"tenantId":           WithTenantID,
+	"qualifier":          func() LogOption { return WithFields(FieldQualifier()) },
+	"retryAttempt":       func() LogOption { return WithFields(FieldRetryAttempt()) },
+	"eventSourceArn":     func() LogOption { return WithFields(FieldEventSourceARN()) },
+	"eventType":          func() LogOption { return WithFields(FieldEventType()) },
+	"parentRequestId":    func() LogOption { return WithFields(FieldParentRequestID()) },
+	"route":              func() LogOption { return WithFields(FieldRoute()) },
+	"eventLagMs":         func() LogOption { return WithFields(FieldEventLagMs()) },
+	"remainingPercent":   func() LogOption { return WithFields(FieldRemainingPercent()) },
+	"initType":           func() LogOption { return WithFields(FieldInitType()) },
+	"snapStartRestored":  func() LogOption { return WithFields(FieldSnapStartRestored()) },
+	"ephemeralStorageMb": func() LogOption { return WithFields(FieldEphemeralStorage()) },
+	"tmpFreeBytes":       func() LogOption { return WithFields(FieldTmpFree()) },
+	"service":            func() LogOption { return WithFields(FieldService()) },
+	"color":              func() LogOption { return WithFields(FieldDeploymentColor()) },
+	"processId":          WithProcessID,
+	"uptimeSeconds":      func() LogOption { return WithFields(FieldUptimeSeconds()) },
+	"recordId":           WithULID,
+	"gid":                WithGoroutineID,
+	"inflight":           WithConcurrencyGauge,
+}
+
+// LoadConfig reads a Config from the JSON file at path, rejecting unknown settings, levels, formats and fields.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	var config Config
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("lambdacontext: invalid log config %s: %w", path, err)
+	}
+	if _, err := strconv.Atoi(config.Level); err != nil && config.Level != "" {
+		if _, ok := SlogLevel(config.Level); !ok {
+			return nil, fmt.Errorf("lambdacontext: invalid log config %s: unknown level %q", path, config.Level)
+		}
+	}
+	if format := strings.ToUpper(config.Format); format != "" && format != "JSON" && format != "TEXT" {
+		return nil, fmt.Errorf("lambdacontext: invalid log config %s: unknown format %q", path, config.Format)
+	}
+	for _, key := range config.Fields {
+		if _, ok := configFields[key]; !ok {
+			return nil, fmt.Errorf("lambdacontext: invalid log config %s: unknown field %q", path, key)
+		}
+	}
+	return &config, nil
+}
+
+// HandlerFromConfig returns a handler as returned by NewLogHandler, configured by c and opts.
+// Unknown fields are ignored. A file output is opened on the first record, creating its directory as needed,
+// and is closed by Close.
+func HandlerFromConfig(c *Config, opts ...LogOption) slog.Handler {
+	options := []LogOption{func(o *logOptions) {
+		o.level, o.format = c.Level, c.Format
+		o.writer = configOutput(c.Output)
+	}}
+	for _, key := range c.Fields {
+		if field, ok := configFields[key]; ok {
+			options = append(options, field())
+		}
+	}
+	return NewLogHandler(append(options, opts...)...)
+}
+
+// configOutput returns the writer of a Config output.
+func configOutput(output string) io.Writer {
+	switch output {
+	case "", "stdout":
+		return os.Stdout
+	case "stderr":
+		return os.Stderr
+	default:
+		return &levelFile{path: output}
+	}
+}
@@ -0,0 +1,99 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfig writes content to a config file in a temporary directory, returning its path.
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "logging.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, `{"level": "DEBUG", "format": "JSON", "fields": ["functionArn"], "output": "app.log"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Config{Level: "DEBUG", Format: "JSON", Fields: []string{"functionArn"}, Output: "app.log"}, config)
+
+	for name, content := range map[string]string{
+		"unknown setting": `{"colour": "blue"}`,
+		"unknown level":   `{"level": "VERBOSE"}`,
+		"unknown format":  `{"format": "XML"}`,
+		"unknown field":   `{"fields": ["hostname"]}`,
+		"malformed":       `{"level": `,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, content))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestHandlerFromConfig(t *testing.T) {
+	withLogFormat(t, "")
+	original := logLevel
+	t.Cleanup(func() { logLevel = original })
+	logLevel = ""
+
+	output := filepath.Join(t.TempDir(), "logs", "app.log")
+	config, err := LoadConfig(writeConfig(t, `{"level": "DEBUG", "format": "json", "fields": ["functionArn", "retryAttempt"], "output": "`+filepath.ToSlash(output)+`"}`))
+	require.NoError(t, err)
+
+	handler := HandlerFromConfig(config)
+	logger := slog.New(handler)
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request", InvokedFunctionArn: "arn:aws:lambda:us-east-1:123456789012:function:test"})
+	logger.DebugContext(WithRetryAttempt(ctx, 2), "debug record")
+	file := handler.(*lambdaHandler).closer.(*levelFile)
+	assert.NotNil(t, file.f)
+	require.NoError(t, Close(handler))
+	assert.Nil(t, file.f, "Close should close the output file")
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &logOutput))
+	assert.Equal(t, "debug record", logOutput["message"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+	assert.Equal(t, "arn:aws:lambda:us-east-1:123456789012:function:test", logOutput["functionArn"])
+	assert.Equal(t, float64(2), logOutput["retryAttempt"])
+	assert.Equal(t, []string{"functionArn", "retryAttempt"}, HandlerFields(handler))
+	assert.Equal(t, slog.LevelDebug, HandlerLevel(handler))
+	assert.Equal(t, slog.LevelInfo, EffectiveLevel())
+}
+
+func TestHandlerFromConfig_EnvWins(t *testing.T) {
+	withLogFormat(t, "Text")
+	original := logLevel
+	t.Cleanup(func() { logLevel = original })
+	logLevel = "ERROR"
+
+	output := filepath.Join(t.TempDir(), "app.log")
+	handler := HandlerFromConfig(&Config{Level: "DEBUG", Format: "JSON", Output: output})
+	assert.False(t, handler.Enabled(context.Background(), slog.LevelWarn))
+	assert.Equal(t, slog.LevelError, HandlerLevel(handler))
+
+	slog.New(handler).Error("error record")
+	require.NoError(t, Close(handler))
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "timestamp="), string(content))
+}
@@ -0,0 +1,59 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// WithStartupDiagnostic logs an INFO record describing the configuration of the handler when it is created,
+// to help debug misconfiguration: its level as logLevel, its format as logFormat, the keys of the configured
+// fields as fields, and its destination as writer, either stdout, stderr, levelFiles, sink or the writer's type.
+func WithStartupDiagnostic() LogOption {
+	return func(o *logOptions) {
+		o.diagnostic = true
+	}
+}
+
+// logDiagnostic logs the startup diagnostic of h, created with options.
+func (h *lambdaHandler) logDiagnostic(options *logOptions, level slog.Level, jsonFormat bool) {
+	ctx := context.Background()
+	if !h.Enabled(ctx, slog.LevelInfo) {
+		return
+	}
+	format := "Text"
+	if jsonFormat {
+		format = "JSON"
+	}
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "log handler configured", 0)
+	r.AddAttrs(
+		slog.String("logLevel", level.String()),
+		slog.String("logFormat", format),
+		slog.Any("fields", h.Fields()),
+		slog.String("writer", writerName(options)),
+	)
+	_ = h.Handle(ctx, r)
+}
+
+// writerName describes the destination of the records of a handler created with options.
+func writerName(options *logOptions) string {
+	switch {
+	case options.levelFiles != nil:
+		return "levelFiles"
+	case options.sink != nil:
+		return "sink"
+	case options.writer == os.Stdout:
+		return "stdout"
+	case options.writer == os.Stderr:
+		return "stderr"
+	default:
+		return fmt.Sprintf("%T", options.writer)
+	}
+}
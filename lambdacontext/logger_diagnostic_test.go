@@ -0,0 +1,43 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStartupDiagnostic(t *testing.T) {
+	withLogFormat(t, "JSON")
+	original := logLevel
+	t.Cleanup(func() { logLevel = original })
+	logLevel = "DEBUG"
+
+	var buf bytes.Buffer
+	NewLogHandler(WithStartupDiagnostic(), WithFunctionARN(), WithFields(FieldRetryAttempt()), withWriter(&buf))
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "INFO", logOutput["level"])
+	assert.Equal(t, "log handler configured", logOutput["message"])
+	assert.Equal(t, "DEBUG", logOutput["logLevel"])
+	assert.Equal(t, "JSON", logOutput["logFormat"])
+	assert.Equal(t, []interface{}{"functionArn", "retryAttempt"}, logOutput["fields"])
+	assert.Equal(t, "*bytes.Buffer", logOutput["writer"])
+}
+
+func TestWriterName(t *testing.T) {
+	assert.Equal(t, "stdout", writerName(&logOptions{writer: os.Stdout}))
+	assert.Equal(t, "stderr", writerName(&logOptions{writer: os.Stderr}))
+	assert.Equal(t, "sink", writerName(&logOptions{writer: os.Stdout, sink: &fakeSink{}}))
+	assert.Equal(t, "levelFiles", writerName(&logOptions{writer: os.Stdout, levelFiles: &levelFiles{}}))
+	assert.Equal(t, "*bytes.Buffer", writerName(&logOptions{writer: &bytes.Buffer{}}))
+}
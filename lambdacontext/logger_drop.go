@@ -0,0 +1,40 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import "log/slog"
+
+// Reasons passed to the callback of WithDropCallback.
+const (
+	// DropReasonPattern is the reason for records dropped by WithSuppressPattern.
+	DropReasonPattern = "pattern"
+	// DropReasonCondition is the reason for records dropped by WithConditionalLogging.
+	DropReasonCondition = "condition"
+	// DropReasonInit is the reason for records dropped by WithSuppressInit.
+	DropReasonInit = "init"
+	// DropReasonBackoff is the reason for records dropped by WithErrorBackoff.
+	DropReasonBackoff = "backoff"
+	// DropReasonAggregated is the reason for records merged into an identical record by WithErrorAggregation.
+	DropReasonAggregated = "aggregated"
+)
+
+// WithDropCallback calls fn with the reason and the record whenever a record is dropped by WithSuppressPattern,
+// WithConditionalLogging, WithSuppressInit, WithErrorBackoff or WithErrorAggregation, such as to count drops
+// in custom metrics. Records below the handler's level are not passed to fn, as they never reach the handler.
+// fn is called synchronously and must not log with the handler.
+func WithDropCallback(fn func(reason string, r slog.Record)) LogOption {
+	return func(o *logOptions) {
+		o.onDrop = fn
+	}
+}
+
+// drop passes r to the drop callback, if any, with reason.
+func (h *lambdaHandler) drop(reason string, r slog.Record) error {
+	if h.onDrop != nil {
+		h.onDrop(reason, r)
+	}
+	return nil
+}
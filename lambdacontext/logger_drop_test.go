@@ -0,0 +1,67 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// drops records the reasons and messages of dropped records.
+type drops struct {
+	reasons  []string
+	messages []string
+}
+
+func (d *drops) record(reason string, r slog.Record) {
+	d.reasons = append(d.reasons, reason)
+	d.messages = append(d.messages, r.Message)
+}
+
+func TestWithDropCallback_SampledOut(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	type sampledKey struct{}
+	var buf bytes.Buffer
+	var dropped drops
+	logger := NewLogger(WithConditionalLogging(func(ctx context.Context) bool {
+		return ctx.Value(sampledKey{}) == true
+	}), WithDropCallback(dropped.record), withWriter(&buf))
+
+	logger.InfoContext(context.WithValue(context.Background(), sampledKey{}, true), "sampled in")
+	logger.InfoContext(context.Background(), "sampled out")
+	logger.ErrorContext(context.Background(), "error")
+
+	assert.Equal(t, []string{DropReasonCondition}, dropped.reasons)
+	assert.Equal(t, []string{"sampled out"}, dropped.messages)
+	assert.Contains(t, buf.String(), "sampled in")
+	assert.NotContains(t, buf.String(), "sampled out")
+}
+
+func TestWithDropCallback_Reasons(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var dropped drops
+	handler := NewLogHandler(WithSuppressPattern(regexp.MustCompile("^noisy")), WithErrorBackoff(), WithErrorAggregation(),
+		WithDropCallback(dropped.record), withWriter(&bytes.Buffer{}))
+	defer Close(handler)
+	logger := slog.New(handler)
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	InvokeStarted(ctx)
+
+	logger.Info("noisy message")
+	logger.ErrorContext(ctx, "failed")
+	logger.ErrorContext(ctx, "failed")
+	logger.ErrorContext(ctx, "failed")
+	InvokeEnded(ctx)
+
+	assert.Equal(t, []string{DropReasonPattern, DropReasonAggregated, DropReasonBackoff}, dropped.reasons)
+}
@@ -0,0 +1,66 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// duplicateDetector tracks the request IDs of recent invocations to detect replayed requests.
+type duplicateDetector struct {
+	mu     sync.Mutex
+	recent []string
+	next   int
+}
+
+// WithDuplicateRequestDetection logs a warning when an invocation reuses the request ID of one of
+// the previous window invocations, which indicates that the request was replayed.
+// Invocations are detected by the invocation hooks called by the lambda package, and the warning
+// is logged when the replayed invocation starts.
+func WithDuplicateRequestDetection(window int) LogOption {
+	return func(o *logOptions) {
+		if window > 0 {
+			o.duplicates = &duplicateDetector{recent: make([]string, 0, window)}
+		}
+	}
+}
+
+// observe records the request ID of a started invocation, and reports whether it is the request ID
+// of a recent previous invocation.
+func (d *duplicateDetector) observe(requestID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if slices.Contains(d.recent, requestID) {
+		return true
+	}
+	if len(d.recent) < cap(d.recent) {
+		d.recent = append(d.recent, requestID)
+	} else {
+		d.recent[d.next] = requestID
+		d.next = (d.next + 1) % len(d.recent)
+	}
+	return false
+}
+
+// duplicateWarning returns the warning record logged for a replayed request.
+func (h *lambdaHandler) duplicateWarning(requestID string) slog.Record {
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "duplicate request ID", 0)
+	r.AddAttrs(h.requestIDAttr(requestID))
+	return r
+}
+
+// warnDuplicate logs a warning if the invocation of ctx, which is starting, replays a recent request.
+func (h *lambdaHandler) warnDuplicate(ctx context.Context) {
+	lc, ok := FromContext(ctx)
+	if !ok || !h.duplicates.observe(lc.AwsRequestID) || !h.handler.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+	_ = h.emit(ctx, lc.AwsRequestID, h.duplicateWarning(lc.AwsRequestID))
+}
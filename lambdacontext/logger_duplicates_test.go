@@ -0,0 +1,57 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDuplicateRequestDetection(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	handler := NewLogHandler(WithDuplicateRequestDetection(2), withWriter(&buf))
+	defer Close(handler)
+	logger := slog.New(handler)
+	var previous context.Context
+	invoke := func(requestID string) {
+		ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: requestID})
+		InvokeStarted(ctx)
+		logger.InfoContext(ctx, "first")
+		if previous != nil {
+			// such as a goroutine of the previous invocation still logging
+			logger.InfoContext(previous, "late")
+		}
+		logger.InfoContext(ctx, "second")
+		InvokeEnded(ctx)
+		previous = ctx
+	}
+
+	invoke("request-1")
+	invoke("request-2")
+	invoke("request-1")
+	invoke("request-3")
+	invoke("request-4")
+	invoke("request-2")
+
+	var warnings []string
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		if logOutput["level"] == "WARN" {
+			assert.Equal(t, "duplicate request ID", logOutput["message"])
+			warnings = append(warnings, logOutput["requestId"].(string))
+		}
+	}
+	assert.Equal(t, []string{"request-1"}, warnings)
+}
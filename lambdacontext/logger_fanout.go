@@ -0,0 +1,71 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// Fanout returns a [slog.Handler] that adds the requestId to each record once, like Inject, and then passes it
+// to each of handlers, allowing a different format per destination, such as JSON to stdout for a new log pipeline
+// and logfmt to a file for an old one:
+//
+//	logger := slog.New(lambdacontext.Fanout(
+//		slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{ReplaceAttr: lambdacontext.ReplaceAttr}),
+//		logfmt.NewHandler(file, nil),
+//	))
+//
+// Each handler only receives the records it is enabled for.
+func Fanout(handlers ...slog.Handler) slog.Handler {
+	return Inject(&fanoutHandler{handlers: handlers})
+}
+
+// fanoutHandler is a slog.Handler passing each record to all of its handlers.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// Enabled implements slog.Handler.
+func (f *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle implements slog.Handler.
+func (f *fanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, h := range f.handlers {
+		if h.Enabled(ctx, record.Level) {
+			errs = append(errs, h.Handle(ctx, record.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements slog.Handler.
+func (f *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return f.with(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+// WithGroup implements slog.Handler.
+func (f *fanoutHandler) WithGroup(name string) slog.Handler {
+	return f.with(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+// with returns a copy of f with each handler replaced by fn applied to it.
+func (f *fanoutHandler) with(fn func(slog.Handler) slog.Handler) *fanoutHandler {
+	handlers := make([]slog.Handler, len(f.handlers))
+	for i, h := range f.handlers {
+		handlers[i] = fn(h)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
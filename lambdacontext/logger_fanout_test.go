@@ -0,0 +1,48 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// The test is in an external package, as the logfmt package imports this one.
+package lambdacontext_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/aws/aws-lambda-go/lambdacontext/logfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFanout(t *testing.T) {
+	var jsonBuf, logfmtBuf bytes.Buffer
+	logger := slog.New(lambdacontext.Fanout(
+		slog.NewJSONHandler(&jsonBuf, &slog.HandlerOptions{ReplaceAttr: lambdacontext.ReplaceAttr}),
+		logfmt.NewHandler(&logfmtBuf, &slog.HandlerOptions{Level: slog.LevelWarn}),
+	))
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "test-request"})
+
+	logger.With("service", "orders").WarnContext(ctx, "order delayed", "orderId", "order-123")
+	logger.InfoContext(ctx, "json only")
+
+	lines := strings.Split(strings.TrimSpace(jsonBuf.String()), "\n")
+	require.Len(t, lines, 2)
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &logOutput))
+	assert.Equal(t, "order delayed", logOutput["message"])
+	assert.Equal(t, "orders", logOutput["service"])
+	assert.Equal(t, "order-123", logOutput["orderId"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+	assert.Equal(t, 1, strings.Count(lines[0], `"requestId"`))
+
+	line := strings.TrimSpace(logfmtBuf.String())
+	assert.NotContains(t, line, "\n")
+	assert.Contains(t, line, `level=WARN message="order delayed" service=orders orderId=order-123 requestId=test-request`)
+	assert.Equal(t, 1, strings.Count(line, "requestId="))
+}
@@ -0,0 +1,476 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// initType is the initialization type of the execution environment from AWS_LAMBDA_INITIALIZATION_TYPE
+// (on-demand, provisioned-concurrency or snap-start).
+var initType = os.Getenv("AWS_LAMBDA_INITIALIZATION_TYPE")
+
+// tmpDir is the path of the function's ephemeral storage.
+var tmpDir = "/tmp"
+
+// FieldEventSourceARN includes the ARN of the event source that triggered the invocation as eventSourceArn.
+// The value is read from the context, see EventSourceARNFromContext. Nothing is emitted when it is absent.
+func FieldEventSourceARN() Field {
+	return Field{key: "eventSourceArn", attr: func(ctx context.Context) (slog.Value, bool) {
+		arn, ok := EventSourceARNFromContext(ctx)
+		return slog.StringValue(arn), ok
+	}}
+}
+
+// FieldEventType includes the name of the Go type the invocation's event was decoded into as eventType.
+// The value is read from the context, see EventTypeFromContext. Nothing is emitted when it is unknown.
+func FieldEventType() Field {
+	return Field{key: "eventType", attr: func(ctx context.Context) (slog.Value, bool) {
+		name, ok := EventTypeFromContext(ctx)
+		return slog.StringValue(name), ok
+	}}
+}
+
+// FieldParentRequestID includes the request ID of the invocation that caused this one as parentRequestId.
+// The value is read from the context, see WithParentRequestID. Nothing is emitted when it is absent.
+func FieldParentRequestID() Field {
+	return Field{key: "parentRequestId", attr: func(ctx context.Context) (slog.Value, bool) {
+		id, ok := ParentRequestIDFromContext(ctx)
+		return slog.StringValue(id), ok
+	}}
+}
+
+// FieldRoute includes the name of the route handling the invocation as route.
+// The value is read from the context, see WithRoute. Nothing is emitted when it is absent.
+func FieldRoute() Field {
+	return Field{key: "route", attr: func(ctx context.Context) (slog.Value, bool) {
+		route, ok := RouteFromContext(ctx)
+		return slog.StringValue(route), ok
+	}}
+}
+
+// FieldEventLagMs includes the milliseconds elapsed between the time of the invocation's event and the record
+// as eventLagMs, such as how long an SQS message waited to be processed.
+// The event time is read from the context, see WithEventTime. Nothing is emitted when it is absent.
+func FieldEventLagMs() Field {
+	return Field{key: "eventLagMs", attr: func(ctx context.Context) (slog.Value, bool) {
+		t, ok := EventTimeFromContext(ctx)
+		return slog.Int64Value(time.Since(t).Milliseconds()), ok
+	}}
+}
+
+// FieldOTelTrace includes the trace and span IDs of the OpenTelemetry span of the context as trace_id and span_id,
+// in lowercase hex following the OpenTelemetry log conventions, rather than in the X-Ray trace ID format.
+// Use it as WithFields(FieldOTelTrace()...). The span context is read from the context, see WithSpanContext,
+// and FieldOTelTraceFrom reads it from the OpenTelemetry SDK instead.
+// Nothing is emitted when it is absent, invalid or not recording.
+func FieldOTelTrace() FieldSet {
+	return FieldOTelTraceFrom(func(ctx context.Context) (traceID [16]byte, spanID [8]byte, recording bool) {
+		sc, _ := SpanContextFromContext(ctx)
+		return sc.TraceID, sc.SpanID, sc.Recording
+	})
+}
+
+// FieldOTelTraceFrom is like FieldOTelTrace, but reads the span of the context with extract,
+// so that handlers instrumented with the OpenTelemetry SDK do not need to copy it with WithSpanContext:
+//
+//	lambdacontext.FieldOTelTraceFrom(func(ctx context.Context) ([16]byte, [8]byte, bool) {
+//		span := trace.SpanFromContext(ctx)
+//		return span.SpanContext().TraceID(), span.SpanContext().SpanID(), span.IsRecording()
+//	})
+func FieldOTelTraceFrom(extract func(ctx context.Context) (traceID [16]byte, spanID [8]byte, recording bool)) FieldSet {
+	span := func(ctx context.Context) ([16]byte, [8]byte, bool) {
+		traceID, spanID, recording := extract(ctx)
+		return traceID, spanID, recording && traceID != [16]byte{} && spanID != [8]byte{}
+	}
+	return FieldSet{
+		{key: "trace_id", attr: func(ctx context.Context) (slog.Value, bool) {
+			traceID, _, ok := span(ctx)
+			return slog.StringValue(hex.EncodeToString(traceID[:])), ok
+		}},
+		{key: "span_id", attr: func(ctx context.Context) (slog.Value, bool) {
+			_, spanID, ok := span(ctx)
+			return slog.StringValue(hex.EncodeToString(spanID[:])), ok
+		}},
+	}
+}
+
+// FieldQualifier includes the alias or version the function was invoked with as qualifier,
+// parsed from the invoked function ARN, such as "live" for arn:aws:lambda:us-east-1:123456789012:function:orders:live.
+// Nothing is emitted when the ARN is unqualified or malformed.
+func FieldQualifier() Field {
+	return Field{key: "qualifier", value: func(lc *LambdaContext) string { return arnQualifier(lc.InvokedFunctionArn) }}
+}
+
+// arnQualifier returns the qualifier of a function ARN, or "" when it is unqualified or malformed.
+func arnQualifier(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) != 8 || parts[0] != "arn" || parts[2] != "lambda" || parts[5] != "function" || parts[6] == "" {
+		return ""
+	}
+	return parts[7]
+}
+
+// FieldRemainingPercent includes the percentage of the function timeout remaining in the invocation as remainingPercent.
+// The timeout is the time between the start of the invocation, see InvokeStarted, and the context deadline.
+// Nothing is emitted when either is unknown.
+func FieldRemainingPercent() Field {
+	return Field{key: "remainingPercent", attr: func(ctx context.Context) (slog.Value, bool) {
+		timeout, ok := invocationTimeout(ctx)
+		if !ok {
+			return slog.Value{}, false
+		}
+		deadline, _ := ctx.Deadline()
+		percent := 100 * float64(time.Until(deadline)) / float64(timeout)
+		return slog.IntValue(int(math.Round(math.Max(0, math.Min(100, percent))))), true
+	}}
+}
+
+// FieldInitType includes the initialization type of the execution environment as initType on every record.
+// The value is read from AWS_LAMBDA_INITIALIZATION_TYPE, and nothing is emitted when it is unset.
+func FieldInitType() Field {
+	return Field{key: "initType", attr: func(context.Context) (slog.Value, bool) {
+		return slog.StringValue(initType), initType != ""
+	}}
+}
+
+// FieldInitTypeOnce is like FieldInitType, but only includes initType on the first record logged by the handler.
+func FieldInitTypeOnce() Field {
+	var logged atomic.Bool
+	return Field{key: "initType", attr: func(context.Context) (slog.Value, bool) {
+		if initType == "" || logged.Swap(true) {
+			return slog.Value{}, false
+		}
+		return slog.StringValue(initType), true
+	}}
+}
+
+// FieldProvisioned includes whether the execution environment was initialized for provisioned concurrency
+// as provisioned. The value is derived from AWS_LAMBDA_INITIALIZATION_TYPE, and nothing is emitted when it is unset.
+func FieldProvisioned() Field {
+	return Field{key: "provisioned", attr: func(context.Context) (slog.Value, bool) {
+		return slog.BoolValue(initType == "provisioned-concurrency"), initType != ""
+	}}
+}
+
+// FieldSnapStartRestored includes whether the execution environment was restored from a SnapStart snapshot
+// as snapStartRestored, see MarkRestored. Nothing is emitted for functions without SnapStart.
+func FieldSnapStartRestored() Field {
+	return Field{key: "snapStartRestored", attr: func(context.Context) (slog.Value, bool) {
+		if Restored() {
+			return slog.BoolValue(true), true
+		}
+		return slog.BoolValue(false), initType == "snap-start"
+	}}
+}
+
+// WithGoroutineID includes the ID of the goroutine that logged each record as gid,
+// to help correlate the records of goroutines spawned by a handler.
+//
+// Goroutine IDs are a debugging aid only: they are parsed from the runtime's stack trace output,
+// which costs a few microseconds per record, and IDs are reused once a goroutine exits.
+func WithGoroutineID() LogOption {
+	return WithFields(Field{key: "gid", attr: func(context.Context) (slog.Value, bool) {
+		id, ok := goroutineID()
+		return slog.Uint64Value(id), ok
+	}})
+}
+
+// WithSyntheticFlag includes synthetic with the value true in the records of invocations for which fn returns true,
+// such as CI and canary invocations, so that they can be filtered out of production dashboards.
+// Nothing is emitted for other invocations.
+func WithSyntheticFlag(fn func(context.Context) bool) LogOption {
+	return WithFields(Field{key: "synthetic", attr: func(ctx context.Context) (slog.Value, bool) {
+		return slog.BoolValue(true), fn(ctx)
+	}})
+}
+
+// processID is the ID of the execution environment's process, generated at init.
+var processID = newProcessID()
+
+// restoredProcessID is the ID of a process restored from a SnapStart snapshot, generated after the restore,
+// as the environments restored from the same snapshot share processID.
+var restoredProcessID = sync.OnceValue(newProcessID)
+
+// processStart approximates the start of the process, for the uptime of FieldUptimeSeconds.
+var processStart = time.Now()
+
+// FieldUptimeSeconds includes the number of whole seconds the process has been running as uptimeSeconds,
+// to help tell cold starts from long-lived execution environments at a glance.
+func FieldUptimeSeconds() Field {
+	return Field{key: "uptimeSeconds", attr: func(context.Context) (slog.Value, bool) {
+		return slog.Int64Value(int64(time.Since(processStart).Seconds())), true
+	}}
+}
+
+// newProcessID returns a random version 4 UUID, or an empty string if the random source fails.
+func newProcessID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return ""
+	}
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+}
+
+// WithProcessID includes a UUID identifying the execution environment's process as processId,
+// to group the records of a warm execution environment, including those logged outside of invocations.
+// A new ID is used once MarkRestored reports a restore from a SnapStart snapshot.
+func WithProcessID() LogOption {
+	return WithFields(Field{key: "processId", attr: func(context.Context) (slog.Value, bool) {
+		id := processID
+		if Restored() {
+			id = restoredProcessID()
+		}
+		return slog.StringValue(id), id != ""
+	}})
+}
+
+// WithConcurrencyGauge includes the number of invocations executing in the process as inflight,
+// counted between the invocation hooks called by the lambda package, to diagnose execution environments
+// handling several invocations at once, see MaxConcurrency.
+func WithConcurrencyGauge() LogOption {
+	return WithFields(Field{key: "inflight", attr: func(context.Context) (slog.Value, bool) {
+		return slog.Int64Value(int64(atomic.LoadInt32(&inflight))), true
+	}})
+}
+
+// WithInvocationRecordIndex includes the ordinal of each record within its invocation as recordIndex,
+// starting at 1, to help detect over-logging. The count restarts with each invocation, as signaled by
+// the invocation hooks called by the lambda package. Nothing is emitted for records logged outside of invocations.
+// The hooks are registered with the handler, and unregistered when it is closed.
+func WithInvocationRecordIndex() LogOption {
+	return func(o *logOptions) {
+		var lock sync.Mutex
+		counts := map[string]int64{}
+		o.invokeStart = append(o.invokeStart, func(ctx context.Context) {
+			if lc, ok := FromContext(ctx); ok {
+				lock.Lock()
+				counts[lc.AwsRequestID] = 0
+				lock.Unlock()
+			}
+		})
+		o.invokeEnd = append(o.invokeEnd, func(ctx context.Context) {
+			if lc, ok := FromContext(ctx); ok {
+				lock.Lock()
+				delete(counts, lc.AwsRequestID)
+				lock.Unlock()
+			}
+		})
+		o.fields = append(o.fields, Field{key: "recordIndex", attr: func(ctx context.Context) (slog.Value, bool) {
+			lc, ok := FromContext(ctx)
+			if !ok {
+				return slog.Value{}, false
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			count, started := counts[lc.AwsRequestID]
+			if !started {
+				return slog.Value{}, false
+			}
+			count++
+			counts[lc.AwsRequestID] = count
+			return slog.Int64Value(count), true
+		}})
+	}
+}
+
+// modulePath is the path of the module of this package.
+const modulePath = "github.com/aws/aws-lambda-go"
+
+// readBuildInfo returns the build info of the running binary, replaced in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// handlerVersion returns the version of this module from the build info, or an empty string if it is unavailable.
+func handlerVersion() string {
+	info, ok := readBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return ""
+}
+
+// WithHandlerVersion includes the version of this module, which produced the record, as logHandlerVersion,
+// so that consumers can relate changes of the log schema to releases.
+// The version is read from the build info of the binary. Nothing is emitted when it is unavailable.
+func WithHandlerVersion() LogOption {
+	version := handlerVersion()
+	return WithFields(Field{key: "logHandlerVersion", attr: func(context.Context) (slog.Value, bool) {
+		return slog.StringValue(version), version != ""
+	}})
+}
+
+// buildFingerprint returns the first 8 hex digits of the SHA-256 of the build info, or an empty string if it is unavailable.
+func buildFingerprint() string {
+	info, ok := readBuildInfo()
+	if !ok {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(info.String()))
+	return hex.EncodeToString(sum[:4])
+}
+
+// WithBuildFingerprint includes a short fingerprint of the binary as build, to map records to the exact artifact
+// that logged them. The fingerprint is derived from the build info of the binary, which includes the versions
+// and sums of its modules, the VCS revision and the build settings, so it only changes when the binary does.
+// Nothing is emitted when the build info is unavailable.
+func WithBuildFingerprint() LogOption {
+	fingerprint := buildFingerprint()
+	return WithFields(Field{key: "build", attr: func(context.Context) (slog.Value, bool) {
+		return slog.StringValue(fingerprint), fingerprint != ""
+	}})
+}
+
+// WithContextValues includes the values extracted from the invocation's context by getters, keyed by name.
+// Nothing is emitted for a getter reporting its value as absent. Fields are added in key order.
+func WithContextValues(getters map[string]func(context.Context) (any, bool)) LogOption {
+	keys := make([]string, 0, len(getters))
+	for key := range getters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]Field, len(keys))
+	for i, key := range keys {
+		getter := getters[key]
+		fields[i] = Field{key: key, attr: func(ctx context.Context) (slog.Value, bool) {
+			v, ok := getter(ctx)
+			return slog.AnyValue(v), ok
+		}}
+	}
+	return WithFields(fields...)
+}
+
+// WithStaticFieldProvider includes the fields returned by provider in every log record, keyed by name.
+// The provider is called once, when the handler is created, so that it may resolve values from external
+// sources during initialization, such as a cost center tag read from Parameter Store. Fields are added in key order.
+func WithStaticFieldProvider(provider func() map[string]string) LogOption {
+	return func(o *logOptions) {
+		values := provider()
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			o.fields = append(o.fields, constantField(key, slog.StringValue(values[key])))
+		}
+	}
+}
+
+// goroutineID parses the current goroutine's ID from the header of its stack trace, "goroutine 123 [running]:".
+func goroutineID() (uint64, bool) {
+	var buf [64]byte
+	stack := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(stack, ' '); i > 0 {
+		stack = stack[:i]
+	}
+	id, err := strconv.ParseUint(string(stack), 10, 64)
+	return id, err == nil
+}
+
+// FieldEphemeralStorage includes the size of the function's ephemeral storage in /tmp, in MB, as ephemeralStorageMb.
+// Lambda does not expose the configured size in the environment, so it is measured from the /tmp file system
+// once per handler. Nothing is emitted when it cannot be measured.
+func FieldEphemeralStorage() Field {
+	var size slog.Value
+	var measured bool
+	var once sync.Once
+	return Field{key: "ephemeralStorageMb", attr: func(context.Context) (slog.Value, bool) {
+		once.Do(func() {
+			if total, _, ok := statTmp(tmpDir); ok {
+				size, measured = slog.Uint64Value(total/(1024*1024)), true
+			}
+		})
+		return size, measured
+	}}
+}
+
+// tmpFreeTTL is how long FieldTmpFree reuses a measurement of the free space in /tmp.
+const tmpFreeTTL = time.Second
+
+// FieldTmpFree includes the free space of the function's ephemeral storage in /tmp, in bytes, as tmpFreeBytes,
+// to help diagnose functions running out of space. The free space is measured at most once per second,
+// to avoid a system call per record. Nothing is emitted when it cannot be measured.
+func FieldTmpFree() Field {
+	var lock sync.Mutex
+	var free slog.Value
+	var measured bool
+	var measuredAt time.Time
+	return Field{key: "tmpFreeBytes", attr: func(context.Context) (slog.Value, bool) {
+		lock.Lock()
+		defer lock.Unlock()
+		if now := time.Now(); now.Sub(measuredAt) >= tmpFreeTTL {
+			_, available, ok := statTmp(tmpDir)
+			free, measured, measuredAt = slog.Uint64Value(available), ok, now
+		}
+		return free, measured
+	}}
+}
+
+// FieldService includes the name of the service as service, following OpenTelemetry conventions.
+// It is equivalent to FieldServiceFrom("SERVICE_NAME", "").
+func FieldService() Field {
+	return FieldServiceFrom("SERVICE_NAME", "")
+}
+
+// FieldServiceFrom includes the name of the service as service. The name is read from OTEL_SERVICE_NAME,
+// falling back to the fallbackEnv environment variable and then to defaultName.
+// Nothing is emitted when all of them are empty.
+func FieldServiceFrom(fallbackEnv, defaultName string) Field {
+	service := os.Getenv("OTEL_SERVICE_NAME")
+	if service == "" && fallbackEnv != "" {
+		service = os.Getenv(fallbackEnv)
+	}
+	if service == "" {
+		service = defaultName
+	}
+	return Field{key: "service", attr: func(context.Context) (slog.Value, bool) {
+		return slog.StringValue(service), service != ""
+	}}
+}
+
+// FieldDeploymentColor includes the active deployment color of a blue/green deployment as color.
+// It is equivalent to FieldDeploymentColorFrom("DEPLOYMENT_COLOR").
+func FieldDeploymentColor() Field {
+	return FieldDeploymentColorFrom("DEPLOYMENT_COLOR")
+}
+
+// FieldDeploymentColorFrom includes the active deployment color, read from the env environment variable, as color.
+// Nothing is emitted when it is unset or empty.
+func FieldDeploymentColorFrom(env string) Field {
+	color := os.Getenv(env)
+	return Field{key: "color", attr: func(context.Context) (slog.Value, bool) {
+		return slog.StringValue(color), color != ""
+	}}
+}
@@ -0,0 +1,603 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logWithFields logs a single record with the given fields and returns the decoded JSON output.
+func logWithFields(t *testing.T, ctx context.Context, fields ...Field) map[string]interface{} {
+	var buf bytes.Buffer
+	handler := &lambdaHandler{
+		handler: slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: ReplaceAttr}),
+		fields:  fields,
+	}
+	slog.New(handler).InfoContext(ctx, "test message")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	return logOutput
+}
+
+func TestFieldRemainingPercent(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(10*time.Second))
+	defer cancel()
+	ctx = NewContext(ctx, &LambdaContext{AwsRequestID: "remaining-percent"})
+
+	assert.NotContains(t, logWithFields(t, ctx, FieldRemainingPercent()), "remainingPercent")
+
+	InvokeStarted(ctx)
+	logOutput := logWithFields(t, ctx, FieldRemainingPercent())
+	require.Contains(t, logOutput, "remainingPercent")
+	assert.InDelta(t, 100, logOutput["remainingPercent"], 1)
+
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldRemainingPercent()), "remainingPercent")
+
+	InvokeEnded(ctx)
+	assert.NotContains(t, logWithFields(t, ctx, FieldRemainingPercent()), "remainingPercent")
+}
+
+// withInitType sets the initialization type for the duration of the test.
+func withInitType(t *testing.T, value string) {
+	original := initType
+	initType = value
+	t.Cleanup(func() { initType = original })
+}
+
+func TestFieldInitType(t *testing.T) {
+	for _, value := range []string{"on-demand", "provisioned-concurrency", "snap-start"} {
+		t.Run(value, func(t *testing.T) {
+			withInitType(t, value)
+			assert.Equal(t, value, logWithFields(t, context.Background(), FieldInitType())["initType"])
+		})
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		withInitType(t, "")
+		assert.NotContains(t, logWithFields(t, context.Background(), FieldInitType()), "initType")
+	})
+}
+
+func TestFieldProvisioned(t *testing.T) {
+	for value, expected := range map[string]bool{
+		"on-demand":               false,
+		"provisioned-concurrency": true,
+		"snap-start":              false,
+	} {
+		t.Run(value, func(t *testing.T) {
+			withInitType(t, value)
+			assert.Equal(t, expected, logWithFields(t, context.Background(), FieldProvisioned())["provisioned"])
+		})
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		withInitType(t, "")
+		assert.NotContains(t, logWithFields(t, context.Background(), FieldProvisioned()), "provisioned")
+	})
+}
+
+func TestFieldInitTypeOnce(t *testing.T) {
+	withInitType(t, "on-demand")
+
+	field := FieldInitTypeOnce()
+	assert.Equal(t, "on-demand", logWithFields(t, context.Background(), field)["initType"])
+	assert.NotContains(t, logWithFields(t, context.Background(), field), "initType")
+}
+
+func TestFieldSnapStartRestored(t *testing.T) {
+	t.Cleanup(func() { atomic.StoreInt32(&restored, 0) })
+
+	withInitType(t, "on-demand")
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldSnapStartRestored()), "snapStartRestored")
+
+	withInitType(t, "snap-start")
+	assert.Equal(t, false, logWithFields(t, context.Background(), FieldSnapStartRestored())["snapStartRestored"])
+
+	MarkRestored()
+	assert.Equal(t, true, logWithFields(t, context.Background(), FieldSnapStartRestored())["snapStartRestored"])
+}
+
+func TestWithProcessID(t *testing.T) {
+	withLogFormat(t, "JSON")
+	t.Cleanup(func() { atomic.StoreInt32(&restored, 0) })
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithProcessID(), withWriter(&buf))
+	logger.Info("outside of an invocation")
+	logger.InfoContext(NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"}), "invocation")
+	MarkRestored()
+	logger.Info("restored")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	ids := make([]interface{}, len(lines))
+	for i, line := range lines {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		ids[i] = logOutput["processId"]
+	}
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, ids[0])
+	assert.Equal(t, ids[0], ids[1])
+	assert.Regexp(t, `^[0-9a-f]{8}-`, ids[2])
+	assert.NotEqual(t, ids[0], ids[2])
+}
+
+func TestWithHandlerVersion(t *testing.T) {
+	withLogFormat(t, "JSON")
+	t.Cleanup(func() { readBuildInfo = debug.ReadBuildInfo })
+
+	tests := []struct {
+		name     string
+		info     *debug.BuildInfo
+		ok       bool
+		expected interface{}
+	}{
+		{"dependency", &debug.BuildInfo{Main: debug.Module{Path: "example.com/function"}, Deps: []*debug.Module{{Path: modulePath, Version: "v1.50.0"}}}, true, "v1.50.0"},
+		{"replaced dependency", &debug.BuildInfo{Deps: []*debug.Module{{Path: modulePath, Version: "v1.50.0", Replace: &debug.Module{Path: "../aws-lambda-go", Version: "v1.50.1"}}}}, true, "v1.50.1"},
+		{"main module", &debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "(devel)"}}, true, "(devel)"},
+		{"not a dependency", &debug.BuildInfo{Main: debug.Module{Path: "example.com/function"}}, true, nil},
+		{"unavailable", nil, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readBuildInfo = func() (*debug.BuildInfo, bool) { return tt.info, tt.ok }
+
+			var buf bytes.Buffer
+			NewLogger(WithHandlerVersion(), withWriter(&buf)).Info("test message")
+
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+			if tt.expected == nil {
+				assert.NotContains(t, logOutput, "logHandlerVersion")
+			} else {
+				assert.Equal(t, tt.expected, logOutput["logHandlerVersion"])
+			}
+		})
+	}
+}
+
+func TestWithGoroutineID(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithGoroutineID(), withWriter(&buf))
+	logger.Info("main goroutine")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("spawned goroutine")
+	}()
+	<-done
+	logger.Info("main goroutine again")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+
+	gids := make([]interface{}, len(lines))
+	for i, line := range lines {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		require.Contains(t, logOutput, "gid")
+		gids[i] = logOutput["gid"]
+	}
+	assert.NotEqual(t, gids[0], gids[1])
+	assert.Equal(t, gids[0], gids[2])
+}
+
+func TestWithStaticFieldProvider(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	calls := 0
+	var buf bytes.Buffer
+	logger := NewLogger(WithStaticFieldProvider(func() map[string]string {
+		calls++
+		return map[string]string{"costCenter": "cc-1234", "team": "orders"}
+	}), withWriter(&buf))
+	logger.Info("first")
+	logger.Info("second")
+
+	assert.Equal(t, 1, calls)
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		assert.Equal(t, "cc-1234", logOutput["costCenter"])
+		assert.Equal(t, "orders", logOutput["team"])
+	}
+	assert.Equal(t, []string{"costCenter", "team"}, HandlerFields(logger.Handler()))
+}
+
+func TestWithContextValues(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	type userIDKey struct{}
+	type orgIDKey struct{}
+	getter := func(key interface{}) func(context.Context) (any, bool) {
+		return func(ctx context.Context) (any, bool) {
+			v := ctx.Value(key)
+			return v, v != nil
+		}
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithContextValues(map[string]func(context.Context) (any, bool){
+		"userId": getter(userIDKey{}),
+		"orgId":  getter(orgIDKey{}),
+	}), withWriter(&buf))
+	logger.InfoContext(context.WithValue(context.Background(), userIDKey{}, "user-123"), "test message")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "user-123", logOutput["userId"])
+	assert.NotContains(t, logOutput, "orgId")
+	assert.Equal(t, []string{"orgId", "userId"}, HandlerFields(logger.Handler()))
+}
+
+func TestWithSyntheticFlag(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	type canaryKey struct{}
+	for name, tc := range map[string]struct {
+		ctx      context.Context
+		expected bool
+	}{
+		"synthetic":  {context.WithValue(context.Background(), canaryKey{}, true), true},
+		"production": {context.Background(), false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLogger(WithSyntheticFlag(func(ctx context.Context) bool {
+				return ctx.Value(canaryKey{}) == true
+			}), withWriter(&buf))
+			logger.InfoContext(tc.ctx, "test message")
+
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+			if tc.expected {
+				assert.Equal(t, true, logOutput["synthetic"])
+			} else {
+				assert.NotContains(t, logOutput, "synthetic")
+			}
+		})
+	}
+}
+
+func TestFieldEventSourceARN(t *testing.T) {
+	ctx := WithEventSourceARN(context.Background(), "arn:aws:sqs:us-east-2:123456789012:my-queue")
+	assert.Equal(t, "arn:aws:sqs:us-east-2:123456789012:my-queue", logWithFields(t, ctx, FieldEventSourceARN())["eventSourceArn"])
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldEventSourceARN()), "eventSourceArn")
+}
+
+func TestFieldEventType(t *testing.T) {
+	ctx := WithEventType(context.Background(), "events.SQSEvent")
+	assert.Equal(t, "events.SQSEvent", logWithFields(t, ctx, FieldEventType())["eventType"])
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldEventType()), "eventType")
+}
+
+// withTmpDir sets the ephemeral storage path for the duration of the test.
+func withTmpDir(t *testing.T, path string) {
+	original := tmpDir
+	tmpDir = path
+	t.Cleanup(func() { tmpDir = original })
+}
+
+func TestFieldEphemeralStorage(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("ephemeral storage is only measured on linux")
+	}
+
+	withTmpDir(t, t.TempDir())
+	logOutput := logWithFields(t, context.Background(), FieldEphemeralStorage())
+	require.Contains(t, logOutput, "ephemeralStorageMb")
+	assert.Greater(t, logOutput["ephemeralStorageMb"], float64(0))
+
+	withTmpDir(t, "/does/not/exist")
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldEphemeralStorage()), "ephemeralStorageMb")
+}
+
+func TestFieldTmpFree(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("ephemeral storage is only measured on linux")
+	}
+
+	withTmpDir(t, t.TempDir())
+	logOutput := logWithFields(t, context.Background(), FieldTmpFree())
+	require.Contains(t, logOutput, "tmpFreeBytes")
+	assert.Greater(t, logOutput["tmpFreeBytes"], float64(0))
+
+	withTmpDir(t, "/does/not/exist")
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldTmpFree()), "tmpFreeBytes")
+}
+
+func TestFieldUptimeSeconds(t *testing.T) {
+	original := processStart
+	t.Cleanup(func() { processStart = original })
+
+	processStart = time.Now().Add(-90 * time.Second)
+	first := logWithFields(t, context.Background(), FieldUptimeSeconds())
+	second := logWithFields(t, context.Background(), FieldUptimeSeconds())
+	require.Contains(t, first, "uptimeSeconds")
+	assert.Equal(t, float64(90), first["uptimeSeconds"])
+	assert.GreaterOrEqual(t, second["uptimeSeconds"], first["uptimeSeconds"])
+}
+
+func TestWithBuildFingerprint(t *testing.T) {
+	withLogFormat(t, "JSON")
+	t.Cleanup(func() { readBuildInfo = debug.ReadBuildInfo })
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{GoVersion: "go1.22.0", Main: debug.Module{Path: "example.com/function", Sum: "h1:abc"}}, true
+	}
+	var buf bytes.Buffer
+	logger := NewLogger(WithBuildFingerprint(), withWriter(&buf))
+	logger.Info("first")
+	logger.Info("second")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var first, second map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &first))
+	require.NoError(t, json.Unmarshal(lines[1], &second))
+	assert.Regexp(t, "^[0-9a-f]{8}$", first["build"])
+	assert.Equal(t, first["build"], second["build"])
+
+	buf.Reset()
+	NewLogger(WithBuildFingerprint(), withWriter(&buf)).Info("same binary")
+	var same map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &same))
+	assert.Equal(t, first["build"], same["build"])
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{GoVersion: "go1.22.0", Main: debug.Module{Path: "example.com/function", Sum: "h1:def"}}, true
+	}
+	buf.Reset()
+	NewLogger(WithBuildFingerprint(), withWriter(&buf)).Info("other binary")
+	var other map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &other))
+	assert.NotEqual(t, first["build"], other["build"])
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return nil, false }
+	buf.Reset()
+	NewLogger(WithBuildFingerprint(), withWriter(&buf)).Info("unavailable")
+	var unavailable map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &unavailable))
+	assert.NotContains(t, unavailable, "build")
+}
+
+func TestWithConcurrencyGauge(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithConcurrencyGauge(), withWriter(&buf))
+	inflightOf := func(ctx context.Context, msg string) float64 {
+		buf.Reset()
+		logger.InfoContext(ctx, msg)
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+		return logOutput["inflight"].(float64)
+	}
+
+	ctx1 := NewContext(context.Background(), &LambdaContext{AwsRequestID: "request-1"})
+	ctx2 := NewContext(context.Background(), &LambdaContext{AwsRequestID: "request-2"})
+	idle := inflightOf(context.Background(), "idle")
+
+	InvokeStarted(ctx1)
+	assert.Equal(t, idle+1, inflightOf(ctx1, "first started"))
+	InvokeStarted(ctx2)
+	assert.Equal(t, idle+2, inflightOf(ctx2, "second started"))
+	InvokeEnded(ctx1)
+	assert.Equal(t, idle+1, inflightOf(ctx2, "first ended"))
+	InvokeEnded(ctx2)
+	assert.Equal(t, idle, inflightOf(context.Background(), "second ended"))
+}
+
+func TestWithInvocationRecordIndex(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	registered := len(invokeStartHooks)
+	handler := NewLogHandler(WithInvocationRecordIndex(), withWriter(&buf))
+	logger := slog.New(handler)
+	indexes := func() []interface{} {
+		var indexes []interface{}
+		for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(line, &logOutput))
+			indexes = append(indexes, logOutput["recordIndex"])
+		}
+		buf.Reset()
+		return indexes
+	}
+
+	logger.Info("init")
+	assert.Equal(t, []interface{}{nil}, indexes())
+
+	for _, requestID := range []string{"request-1", "request-2"} {
+		ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: requestID})
+		InvokeStarted(ctx)
+		logger.InfoContext(ctx, "first")
+		logger.InfoContext(ctx, "second")
+		logger.InfoContext(ctx, "third")
+		InvokeEnded(ctx)
+		assert.Equal(t, []interface{}{float64(1), float64(2), float64(3)}, indexes(), requestID)
+	}
+
+	assert.Len(t, invokeStartHooks, registered+1)
+	require.NoError(t, Close(handler))
+	assert.Len(t, invokeStartHooks, registered)
+}
+
+func TestFieldParentRequestID(t *testing.T) {
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "child-request"})
+	ctx = WithParentRequestID(ctx, "parent-request")
+
+	logOutput := logWithFields(t, ctx, FieldParentRequestID())
+	assert.Equal(t, "parent-request", logOutput["parentRequestId"])
+	assert.Equal(t, "child-request", logOutput["requestId"])
+
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldParentRequestID()), "parentRequestId")
+}
+
+func TestFieldRoute(t *testing.T) {
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	ctx = WithRoute(ctx, "orders")
+
+	logOutput := logWithFields(t, ctx, FieldRoute())
+	assert.Equal(t, "orders", logOutput["route"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldRoute()), "route")
+}
+
+func TestFieldOTelTrace(t *testing.T) {
+	sc := SpanContext{
+		TraceID:   [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:    [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		Recording: true,
+	}
+	logOutput := logWithFields(t, WithSpanContext(context.Background(), sc), FieldOTelTrace()...)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", logOutput["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", logOutput["span_id"])
+
+	sc.Recording = false
+	logOutput = logWithFields(t, WithSpanContext(context.Background(), sc), FieldOTelTrace()...)
+	assert.NotContains(t, logOutput, "trace_id")
+	assert.NotContains(t, logOutput, "span_id")
+
+	logOutput = logWithFields(t, WithSpanContext(context.Background(), SpanContext{Recording: true}), FieldOTelTrace()...)
+	assert.NotContains(t, logOutput, "trace_id")
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldOTelTrace()...), "trace_id")
+}
+
+func TestFieldOTelTraceFrom(t *testing.T) {
+	type spanKey struct{}
+	type span struct {
+		traceID   [16]byte
+		spanID    [8]byte
+		recording bool
+	}
+	fields := FieldOTelTraceFrom(func(ctx context.Context) ([16]byte, [8]byte, bool) {
+		s, _ := ctx.Value(spanKey{}).(span)
+		return s.traceID, s.spanID, s.recording
+	})
+
+	s := span{
+		traceID:   [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		spanID:    [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		recording: true,
+	}
+	logOutput := logWithFields(t, context.WithValue(context.Background(), spanKey{}, s), fields...)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", logOutput["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", logOutput["span_id"])
+
+	s.recording = false
+	logOutput = logWithFields(t, context.WithValue(context.Background(), spanKey{}, s), fields...)
+	assert.NotContains(t, logOutput, "trace_id")
+	assert.NotContains(t, logOutput, "span_id")
+
+	assert.NotContains(t, logWithFields(t, context.Background(), fields...), "trace_id")
+}
+
+func TestFieldEventLagMs(t *testing.T) {
+	ctx := WithEventTime(context.Background(), time.Now().Add(-2*time.Second))
+
+	lag := logWithFields(t, ctx, FieldEventLagMs())["eventLagMs"]
+	assert.GreaterOrEqual(t, lag, float64(2000))
+	assert.Less(t, lag, float64(60000))
+
+	assert.NotContains(t, logWithFields(t, context.Background(), FieldEventLagMs()), "eventLagMs")
+}
+
+func TestFieldQualifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		arn      string
+		expected string
+	}{
+		{name: "alias", arn: "arn:aws:lambda:us-east-1:123456789012:function:orders:live", expected: "live"},
+		{name: "version", arn: "arn:aws:lambda:us-east-1:123456789012:function:orders:42", expected: "42"},
+		{name: "latest", arn: "arn:aws:lambda:us-east-1:123456789012:function:orders:$LATEST", expected: "$LATEST"},
+		{name: "unqualified", arn: "arn:aws:lambda:us-east-1:123456789012:function:orders"},
+		{name: "not a function", arn: "arn:aws:lambda:us-east-1:123456789012:layer:orders:3"},
+		{name: "malformed", arn: "orders:live"},
+		{name: "empty", arn: ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request", InvokedFunctionArn: tc.arn})
+			logOutput := logWithFields(t, ctx, FieldQualifier())
+			if tc.expected == "" {
+				assert.NotContains(t, logOutput, "qualifier")
+			} else {
+				assert.Equal(t, tc.expected, logOutput["qualifier"])
+			}
+		})
+	}
+}
+
+func TestFieldService(t *testing.T) {
+	tests := []struct {
+		name        string
+		otel        string
+		fallback    string
+		defaultName string
+		expected    interface{}
+	}{
+		{"otel env", "otel-service", "fallback-service", "default-service", "otel-service"},
+		{"fallback env", "", "fallback-service", "default-service", "fallback-service"},
+		{"default", "", "", "default-service", "default-service"},
+		{"unset", "", "", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_SERVICE_NAME", tt.otel)
+			t.Setenv("MY_SERVICE_NAME", tt.fallback)
+
+			logOutput := logWithFields(t, context.Background(), FieldServiceFrom("MY_SERVICE_NAME", tt.defaultName))
+			if tt.expected == nil {
+				assert.NotContains(t, logOutput, "service")
+			} else {
+				assert.Equal(t, tt.expected, logOutput["service"])
+			}
+		})
+	}
+
+	t.Run("SERVICE_NAME", func(t *testing.T) {
+		t.Setenv("OTEL_SERVICE_NAME", "")
+		t.Setenv("SERVICE_NAME", "env-service")
+		assert.Equal(t, "env-service", logWithFields(t, context.Background(), FieldService())["service"])
+	})
+}
+
+func TestFieldDeploymentColor(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		t.Setenv("DEPLOYMENT_COLOR", "blue")
+		assert.Equal(t, "blue", logWithFields(t, context.Background(), FieldDeploymentColor())["color"])
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		t.Setenv("DEPLOYMENT_COLOR", "")
+		assert.NotContains(t, logWithFields(t, context.Background(), FieldDeploymentColor()), "color")
+	})
+
+	t.Run("custom env", func(t *testing.T) {
+		t.Setenv("SLOT_COLOR", "green")
+		assert.Equal(t, "green", logWithFields(t, context.Background(), FieldDeploymentColorFrom("SLOT_COLOR"))["color"])
+	})
+}
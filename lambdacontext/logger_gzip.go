@@ -0,0 +1,93 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"os"
+	"sync"
+)
+
+// gzipBlockSize is the size of uncompressed records at which gzipWriter writes a block.
+const gzipBlockSize = 1 << 20
+
+// gzipWriter buffers the records written to it, and writes them to out as framed gzip-compressed blocks.
+type gzipWriter struct {
+	lock    sync.Mutex
+	out     io.Writer
+	buf     bytes.Buffer
+	records int // number of writes to buf
+}
+
+// WithGzip writes records to w as gzip-compressed blocks, to reduce egress to custom sinks.
+// Records are buffered until Flush or Close is called, or until about 1 MiB of records is buffered.
+// Each block is framed by its length, as a 4-byte big-endian integer, followed by a complete gzip stream
+// of the newline-terminated records. Close also closes w, if it is an io.Closer other than stdout or stderr.
+func WithGzip(w io.Writer) LogOption {
+	return func(o *logOptions) {
+		o.writer = &gzipWriter{out: w}
+	}
+}
+
+// Write implements io.Writer.
+func (w *gzipWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.buf.Write(p)
+	w.records++
+	if w.buf.Len() >= gzipBlockSize {
+		if err := w.writeBlock(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush writes the buffered records as a block.
+func (w *gzipWriter) flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.writeBlock()
+}
+
+// Close implements io.Closer, flushing the buffered records and closing the destination,
+// unless it is stdout or stderr.
+func (w *gzipWriter) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	if w.out == os.Stdout || w.out == os.Stderr {
+		return nil
+	}
+	if c, ok := w.out.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// writeBlock compresses the buffered records and writes them as a framed block. w.lock must be held.
+func (w *gzipWriter) writeBlock() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	var block bytes.Buffer
+	block.Write(make([]byte, 4))
+	zw := gzip.NewWriter(&block)
+	if _, err := zw.Write(w.buf.Bytes()); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(block.Bytes(), uint32(block.Len()-4))
+	w.buf.Reset()
+	w.records = 0
+	_, err := w.out.Write(block.Bytes())
+	return err
+}
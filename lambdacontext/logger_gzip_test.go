@@ -0,0 +1,95 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closeRecorder is a bytes.Buffer recording whether it was closed.
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+// readGzipBlocks returns the messages of the records of each block in p.
+func readGzipBlocks(t *testing.T, p []byte) [][]string {
+	var blocks [][]string
+	for len(p) > 0 {
+		require.GreaterOrEqual(t, len(p), 4)
+		size := binary.BigEndian.Uint32(p)
+		require.GreaterOrEqual(t, uint32(len(p)-4), size)
+		zr, err := gzip.NewReader(bytes.NewReader(p[4 : 4+size]))
+		require.NoError(t, err)
+		var messages []string
+		scanner := bufio.NewScanner(zr)
+		for scanner.Scan() {
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &logOutput))
+			messages = append(messages, logOutput["message"].(string))
+		}
+		require.NoError(t, scanner.Err())
+		blocks = append(blocks, messages)
+		p = p[4+size:]
+	}
+	return blocks
+}
+
+func TestWithGzip(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var sink closeRecorder
+	handler := NewLogHandler(WithGzip(&sink))
+	logger := slog.New(handler)
+	logger.Info("first")
+	logger.Info("second")
+	assert.Zero(t, sink.Len())
+
+	require.NoError(t, Flush(handler))
+	logger.Info("third")
+	require.NoError(t, Close(handler))
+
+	assert.True(t, sink.closed)
+	assert.Equal(t, [][]string{{"first", "second"}, {"third"}}, readGzipBlocks(t, sink.Bytes()))
+}
+
+func TestWithGzip_Stdout(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	original := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() { os.Stdout = original })
+
+	handler := NewLogHandler(WithGzip(os.Stdout))
+	slog.New(handler).Info("first")
+	require.NoError(t, Close(handler))
+
+	_, err = w.Write([]byte("still open"))
+	require.NoError(t, err, "Close should not close stdout")
+	require.NoError(t, w.Close())
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"first"}}, readGzipBlocks(t, bytes.TrimSuffix(content, []byte("still open"))))
+}
@@ -0,0 +1,86 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+)
+
+// RecordHash returns a hash of the level, message and attrs of r, for detecting duplicate records.
+// The time of r is ignored, and attrs are hashed in key order, then value order for repeated keys, so that records
+// differing only in the order of their attrs hash equally. Attrs within groups are hashed with their dotted keys,
+// and values are hashed with their kind, so that the string "42" and the int 42 hash differently.
+func RecordHash(r slog.Record) uint64 {
+	var attrs []slog.Attr
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = appendFlattened(attrs, "", attr)
+		return true
+	})
+	values := make([]string, len(attrs))
+	for i, attr := range attrs {
+		values[i] = attr.Value.String()
+	}
+	sort.Sort(hashedAttrs{attrs, values})
+
+	h := fnv.New64a()
+	writeHashed(h, r.Level.String())
+	writeHashed(h, r.Message)
+	for i, attr := range attrs {
+		writeHashed(h, attr.Key)
+		h.Write([]byte{byte(attr.Value.Kind())})
+		writeHashed(h, values[i])
+	}
+	return h.Sum64()
+}
+
+// hashedAttrs sorts attrs by key, value and kind, with values holding the string of each value.
+type hashedAttrs struct {
+	attrs  []slog.Attr
+	values []string
+}
+
+func (a hashedAttrs) Len() int { return len(a.attrs) }
+
+func (a hashedAttrs) Less(i, j int) bool {
+	if a.attrs[i].Key != a.attrs[j].Key {
+		return a.attrs[i].Key < a.attrs[j].Key
+	}
+	if a.values[i] != a.values[j] {
+		return a.values[i] < a.values[j]
+	}
+	return a.attrs[i].Value.Kind() < a.attrs[j].Value.Kind()
+}
+
+func (a hashedAttrs) Swap(i, j int) {
+	a.attrs[i], a.attrs[j] = a.attrs[j], a.attrs[i]
+	a.values[i], a.values[j] = a.values[j], a.values[i]
+}
+
+// appendFlattened appends attr to attrs, replacing groups with their attrs keyed by their dotted path.
+func appendFlattened(attrs []slog.Attr, prefix string, attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(attrs, slog.Attr{Key: prefix + attr.Key, Value: attr.Value})
+	}
+	if attr.Key != "" {
+		prefix += attr.Key + "."
+	}
+	for _, member := range attr.Value.Group() {
+		attrs = appendFlattened(attrs, prefix, member)
+	}
+	return attrs
+}
+
+// writeHashed writes s to h prefixed by its length, so that consecutive strings cannot run into each other.
+func writeHashed(h hash.Hash64, s string) {
+	var n [binary.MaxVarintLen64]byte
+	h.Write(n[:binary.PutUvarint(n[:], uint64(len(s)))])
+	h.Write([]byte(s))
+}
@@ -0,0 +1,46 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordHash(t *testing.T) {
+	newRecord := func(level slog.Level, message string, attrs ...slog.Attr) slog.Record {
+		r := slog.NewRecord(time.Now(), level, message, 0)
+		r.AddAttrs(attrs...)
+		return r
+	}
+	base := newRecord(slog.LevelInfo, "order processed", slog.String("orderId", "order-123"), slog.Int("amount", 42))
+
+	assert.Equal(t, RecordHash(base), RecordHash(base.Clone()))
+	assert.Equal(t, RecordHash(base),
+		RecordHash(newRecord(slog.LevelInfo, "order processed", slog.Int("amount", 42), slog.String("orderId", "order-123"))))
+	assert.Equal(t,
+		RecordHash(newRecord(slog.LevelInfo, "m", slog.Group("a", slog.Int("x", 1), slog.Int("y", 2)))),
+		RecordHash(newRecord(slog.LevelInfo, "m", slog.Group("a", slog.Int("y", 2), slog.Int("x", 1)))))
+	assert.Equal(t,
+		RecordHash(newRecord(slog.LevelInfo, "m", slog.String("tag", "a"), slog.String("tag", "b"))),
+		RecordHash(newRecord(slog.LevelInfo, "m", slog.String("tag", "b"), slog.String("tag", "a"))))
+
+	for name, r := range map[string]slog.Record{
+		"level":   newRecord(slog.LevelWarn, "order processed", slog.String("orderId", "order-123"), slog.Int("amount", 42)),
+		"message": newRecord(slog.LevelInfo, "order shipped", slog.String("orderId", "order-123"), slog.Int("amount", 42)),
+		"value":   newRecord(slog.LevelInfo, "order processed", slog.String("orderId", "order-456"), slog.Int("amount", 42)),
+		"missing": newRecord(slog.LevelInfo, "order processed", slog.String("orderId", "order-123")),
+		"kind":    newRecord(slog.LevelInfo, "order processed", slog.String("orderId", "order-123"), slog.String("amount", "42")),
+		"joined":  newRecord(slog.LevelInfo, "order processedorderId", slog.String("", "order-123"), slog.Int("amount", 42)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.NotEqual(t, RecordHash(base), RecordHash(r))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// heartbeats tracks the heartbeat goroutines of active invocations, keyed by request ID.
+type heartbeats struct {
+	lock    sync.Mutex
+	handler *lambdaHandler
+	stops   map[string]chan struct{}
+}
+
+// WithHeartbeat logs an INFO heartbeat record every interval while an invocation is active, with the milliseconds
+// since the invocation started as uptimeMs, to show that long-running invocations, such as streaming responses,
+// are alive. Heartbeats start and stop with the invocation hooks called by the lambda package, stopping once
+// the response has been sent, including a streamed response body. Close the handler to stop logging heartbeats.
+func WithHeartbeat(interval time.Duration) LogOption {
+	return func(o *logOptions) {
+		o.heartbeat = interval
+	}
+}
+
+// startHeartbeats logs heartbeats to h during each invocation.
+func startHeartbeats(h *lambdaHandler, interval time.Duration) {
+	hb := &heartbeats{handler: h, stops: map[string]chan struct{}{}}
+	h.onInvokeStart(func(ctx context.Context) {
+		if lc, ok := FromContext(ctx); ok {
+			hb.start(ctx, lc.AwsRequestID, interval)
+		}
+	})
+	h.onInvokeEnd(func(ctx context.Context) {
+		if lc, ok := FromContext(ctx); ok {
+			hb.stop(lc.AwsRequestID)
+		}
+	})
+}
+
+// start logs a heartbeat for the invocation of ctx every interval, until stop is called or ctx is done.
+func (hb *heartbeats) start(ctx context.Context, requestID string, interval time.Duration) {
+	stop := make(chan struct{})
+	hb.lock.Lock()
+	if previous, ok := hb.stops[requestID]; ok {
+		close(previous)
+	}
+	hb.stops[requestID] = stop
+	hb.lock.Unlock()
+
+	start := time.Now()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if !hb.handler.Enabled(ctx, slog.LevelInfo) {
+					continue
+				}
+				r := slog.NewRecord(now, slog.LevelInfo, "heartbeat", 0)
+				r.AddAttrs(slog.Int64("uptimeMs", now.Sub(start).Milliseconds()))
+				_ = hb.handler.Handle(ctx, r)
+			}
+		}
+	}()
+}
+
+// stop stops the heartbeats of the invocation with requestID.
+func (hb *heartbeats) stop(requestID string) {
+	hb.lock.Lock()
+	defer hb.lock.Unlock()
+	if stop, ok := hb.stops[requestID]; ok {
+		close(stop)
+		delete(hb.stops, requestID)
+	}
+}
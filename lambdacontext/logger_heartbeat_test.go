@@ -0,0 +1,82 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func TestWithHeartbeat(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf syncBuffer
+	handler := NewLogHandler(WithHeartbeat(5*time.Millisecond), withWriter(&buf))
+	defer Close(handler)
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	goroutines := runtime.NumGoroutine()
+
+	InvokeStarted(ctx)
+	time.Sleep(30 * time.Millisecond)
+	InvokeEnded(ctx)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.NotEmpty(t, lines[0])
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &logOutput))
+	assert.Equal(t, "heartbeat", logOutput["message"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+	assert.GreaterOrEqual(t, logOutput["uptimeMs"], float64(5))
+
+	// heartbeats stop when the invocation ends, without leaking their goroutine
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > goroutines && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines)
+	count := len(buf.Bytes())
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, count, len(buf.Bytes()))
+}
+
+func TestWithHeartbeat_CloseUnregistersHooks(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf syncBuffer
+	handler := NewLogHandler(WithHeartbeat(time.Millisecond), withWriter(&buf))
+	require.NoError(t, Close(handler))
+
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	InvokeStarted(ctx)
+	time.Sleep(10 * time.Millisecond)
+	InvokeEnded(ctx)
+	assert.Empty(t, buf.Bytes())
+}
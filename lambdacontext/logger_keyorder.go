@@ -0,0 +1,112 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"slices"
+)
+
+// keyOrderWriter re-encodes each JSON record written to it with its keys ordered by cmp.
+// The pinned keys are kept at the start of the record, in the order given.
+type keyOrderWriter struct {
+	out    io.Writer
+	cmp    func(a, b string) int
+	pinned []string
+}
+
+// Write implements io.Writer. Records that fail to decode are written unchanged.
+func (w *keyOrderWriter) Write(p []byte) (int, error) {
+	ordered, err := orderKeys(bytes.TrimRight(p, "\n"), w.cmp, w.pinned)
+	if err != nil {
+		return w.out.Write(p)
+	}
+	if _, err := w.out.Write(append(ordered, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// jsonMember is a key and its undecoded value within a JSON object.
+type jsonMember struct {
+	key   string
+	value json.RawMessage
+}
+
+// orderKeys returns the JSON value raw with the keys of all of its objects ordered by cmp.
+// Keys in pinned are placed first in the top-level object only. Non-object values are returned unchanged.
+func orderKeys(raw []byte, cmp func(a, b string) int, pinned []string) ([]byte, error) {
+	members, ok, err := decodeObject(raw)
+	if err != nil || !ok {
+		return raw, err
+	}
+	for i := range members {
+		if members[i].value, err = orderKeys(members[i].value, cmp, nil); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortStableFunc(members, func(a, b jsonMember) int {
+		ai, bi := slices.Index(pinned, a.key), slices.Index(pinned, b.key)
+		switch {
+		case ai >= 0 && bi >= 0:
+			return ai - bi
+		case ai >= 0:
+			return -1
+		case bi >= 0:
+			return 1
+		}
+		return cmp(a.key, b.key)
+	})
+	return encodeObject(members)
+}
+
+// decodeObject decodes the members of the JSON object raw in their original order.
+// It reports false if raw is not an object.
+func decodeObject(raw []byte) ([]jsonMember, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, false, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, false, nil
+	}
+	var members []jsonMember
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false, err
+		}
+		members = append(members, jsonMember{key: tok.(string), value: value})
+	}
+	return members, true, nil
+}
+
+// encodeObject encodes members as a JSON object, preserving their order.
+func encodeObject(members []jsonMember) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
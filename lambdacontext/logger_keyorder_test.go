@@ -0,0 +1,44 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithKeyOrder(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithKeyOrder(strings.Compare), WithFunctionARN(), withWriter(&buf))
+
+	lc := &LambdaContext{AwsRequestID: "test-request", InvokedFunctionArn: "test-arn"}
+	ctx := NewContext(context.Background(), lc)
+	logger.InfoContext(ctx, "test message", "zeta", 1, "alpha", "a", "group", map[string]int{"y": 1, "b": 2})
+
+	line := buf.String()
+	assert.True(t, strings.HasPrefix(line, `{"timestamp":`), line)
+	assert.True(t, strings.HasSuffix(line, "\n"), line)
+
+	expectedOrder := []string{`"timestamp"`, `"level"`, `"message"`, `"alpha"`, `"functionArn"`, `"group"`, `"b"`, `"y"`, `"requestId"`, `"zeta"`}
+	last := -1
+	for _, key := range expectedOrder {
+		i := strings.Index(line, key)
+		assert.Greater(t, i, last, "expected %s after the previous key in %s", key, line)
+		last = i
+	}
+}
+
+func TestOrderKeys_NonObject(t *testing.T) {
+	ordered, err := orderKeys([]byte(`[1,2]`), strings.Compare, []string{"message"})
+	assert.NoError(t, err)
+	assert.Equal(t, `[1,2]`, string(ordered))
+}
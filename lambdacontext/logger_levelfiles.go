@@ -0,0 +1,175 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+)
+
+// levelFiles are the files that records are routed to by level, for local development.
+type levelFiles struct {
+	files   []*levelFile
+	signals chan os.Signal
+	once    sync.Once
+}
+
+// levelFile is a file opened on first write, and reopened on the next write after a rotation signal.
+type levelFile struct {
+	level slog.Level
+	path  string
+	mu    sync.Mutex
+	f     *os.File
+}
+
+// WithLevelFiles writes records to files instead of stdout, for local development such as with the
+// Runtime Interface Emulator. A record is written to each file whose level is at or below the record's level,
+// so that {slog.LevelError: "errors.log", slog.LevelDebug: "all.log"} writes errors to both files.
+// Files are appended to, their directories are created as needed, and they are reopened after a SIGHUP,
+// to support log rotation. Batching does not apply to level files.
+//
+// WithLevelFiles is a no-op when running in the Lambda execution environment.
+func WithLevelFiles(files map[slog.Level]string) LogOption {
+	return func(o *logOptions) {
+		if initType != "" || len(files) == 0 {
+			return
+		}
+		o.levelFiles = newLevelFiles(files)
+	}
+}
+
+// newLevelFiles returns the level files for the given paths, reopened on SIGHUP until closed.
+func newLevelFiles(paths map[slog.Level]string) *levelFiles {
+	lf := &levelFiles{signals: make(chan os.Signal, 1)}
+	for level, path := range paths {
+		lf.files = append(lf.files, &levelFile{level: level, path: path})
+	}
+	sort.Slice(lf.files, func(i, j int) bool { return lf.files[i].level < lf.files[j].level })
+	signal.Notify(lf.signals, syscall.SIGHUP)
+	go func() {
+		for range lf.signals {
+			lf.reopen()
+		}
+	}()
+	return lf
+}
+
+// reopen closes the files, so that they are reopened by the next write.
+func (lf *levelFiles) reopen() {
+	for _, f := range lf.files {
+		_ = f.Close()
+	}
+}
+
+// Close stops reopening the files on SIGHUP and closes them.
+func (lf *levelFiles) Close() error {
+	lf.once.Do(func() {
+		signal.Stop(lf.signals)
+		close(lf.signals)
+	})
+	var errs []error
+	for _, f := range lf.files {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// router returns a handler routing records to the files, each encoded by a handler returned by newHandler.
+func (lf *levelFiles) router(newHandler func(io.Writer) slog.Handler) *levelRouter {
+	routes := make([]levelRoute, len(lf.files))
+	for i, f := range lf.files {
+		routes[i] = levelRoute{level: f.level, handler: newHandler(f)}
+	}
+	return &levelRouter{routes: routes}
+}
+
+// Write implements io.Writer, opening the file if needed.
+func (f *levelFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.f == nil {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			return 0, err
+		}
+		file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return 0, err
+		}
+		f.f = file
+	}
+	return f.f.Write(p)
+}
+
+// Close implements io.Closer, closing the file if it is open. It is reopened by the next write.
+func (f *levelFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.f == nil {
+		return nil
+	}
+	err := f.f.Close()
+	f.f = nil
+	return err
+}
+
+// levelRoute is the handler of records at or above a level.
+type levelRoute struct {
+	level   slog.Level
+	handler slog.Handler
+}
+
+// levelRouter is a slog.Handler passing each record to the handlers of the routes matching its level.
+type levelRouter struct {
+	routes []levelRoute
+}
+
+// Enabled implements slog.Handler.
+func (r *levelRouter) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, route := range r.routes {
+		if level >= route.level && route.handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle implements slog.Handler.
+func (r *levelRouter) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, route := range r.routes {
+		if record.Level >= route.level && route.handler.Enabled(ctx, record.Level) {
+			errs = append(errs, route.handler.Handle(ctx, record.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements slog.Handler.
+func (r *levelRouter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return r.with(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+// WithGroup implements slog.Handler.
+func (r *levelRouter) WithGroup(name string) slog.Handler {
+	return r.with(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+// with returns a copy of r with each route's handler replaced by fn applied to it.
+func (r *levelRouter) with(fn func(slog.Handler) slog.Handler) *levelRouter {
+	routes := make([]levelRoute, len(r.routes))
+	for i, route := range r.routes {
+		routes[i] = levelRoute{level: route.level, handler: fn(route.handler)}
+	}
+	return &levelRouter{routes: routes}
+}
@@ -0,0 +1,84 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readMessages returns the messages of the JSON records in the file at path.
+func readMessages(t *testing.T, path string) []string {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var messages []string
+	for _, line := range bytes.Split(bytes.TrimSpace(content), []byte("\n")) {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		messages = append(messages, logOutput["message"].(string))
+	}
+	return messages
+}
+
+func TestWithLevelFiles(t *testing.T) {
+	withLogFormat(t, "JSON")
+	withInitType(t, "")
+
+	dir := t.TempDir()
+	errorsPath := filepath.Join(dir, "errors", "errors.log")
+	allPath := filepath.Join(dir, "all.log")
+
+	var stdout bytes.Buffer
+	handler := NewLogHandler(WithLevelFiles(map[slog.Level]string{
+		slog.LevelError: errorsPath,
+		slog.LevelInfo:  allPath,
+	}), withWriter(&stdout))
+	defer Close(handler)
+
+	logger := slog.New(handler).With("service", "orders")
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	assert.Equal(t, []string{"error"}, readMessages(t, errorsPath))
+	assert.Equal(t, []string{"info", "warn", "error"}, readMessages(t, allPath))
+	assert.Empty(t, stdout.String())
+}
+
+func TestWithLevelFiles_Reopen(t *testing.T) {
+	withLogFormat(t, "JSON")
+	withInitType(t, "")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	handler := NewLogHandler(WithLevelFiles(map[slog.Level]string{slog.LevelInfo: path}))
+	defer Close(handler)
+	logger := slog.New(handler)
+
+	logger.Info("before rotation")
+	require.NoError(t, os.Rename(path, path+".1"))
+	handler.(*lambdaHandler).closer.(*levelFiles).reopen()
+	logger.Info("after rotation")
+
+	assert.Equal(t, []string{"before rotation"}, readMessages(t, path+".1"))
+	assert.Equal(t, []string{"after rotation"}, readMessages(t, path))
+}
+
+func TestWithLevelFiles_NoopInLambda(t *testing.T) {
+	withInitType(t, "on-demand")
+
+	options := &logOptions{}
+	WithLevelFiles(map[slog.Level]string{slog.LevelInfo: filepath.Join(t.TempDir(), "app.log")})(options)
+	assert.Nil(t, options.levelFiles)
+}
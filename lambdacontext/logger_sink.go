@@ -0,0 +1,81 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Sink is a destination for encoded log records, such as an HTTP endpoint or a socket.
+// Write is called once for each record, with the context passed to the logger, and must not retain record.
+type Sink interface {
+	Write(ctx context.Context, record []byte) error
+	Close() error
+}
+
+// WithSink writes records to sink instead of stdout. Records are encoded, and include the requestId and
+// configured fields, the same as when written to stdout. Batching does not apply to sinks.
+// Call Close on shutdown, for example from a lambda.WithEnableSIGTERM callback, to close the sink.
+func WithSink(sink Sink) LogOption {
+	return func(o *logOptions) {
+		o.sink = sink
+	}
+}
+
+// sinkBuffer holds the encoding of the record being handled by a sinkHandler.
+type sinkBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (b *sinkBuffer) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+// sinkHandler is a slog.Handler passing the records encoded by handler to a Sink, with the record's context.
+type sinkHandler struct {
+	handler slog.Handler
+	buf     *sinkBuffer
+	sink    Sink
+}
+
+// newSinkHandler returns a handler writing to sink the records encoded by the handler returned by newHandler.
+func newSinkHandler(sink Sink, newHandler func(*sinkBuffer) slog.Handler) *sinkHandler {
+	buf := &sinkBuffer{}
+	return &sinkHandler{handler: newHandler(buf), buf: buf, sink: sink}
+}
+
+// Enabled implements slog.Handler.
+func (h *sinkHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *sinkHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.buf.lock.Lock()
+	err := h.handler.Handle(ctx, r)
+	record := bytes.Clone(h.buf.buf.Bytes())
+	h.buf.buf.Reset()
+	h.buf.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	return h.sink.Write(ctx, record)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *sinkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sinkHandler{handler: h.handler.WithAttrs(attrs), buf: h.buf, sink: h.sink}
+}
+
+// WithGroup implements slog.Handler.
+func (h *sinkHandler) WithGroup(name string) slog.Handler {
+	return &sinkHandler{handler: h.handler.WithGroup(name), buf: h.buf, sink: h.sink}
+}
@@ -0,0 +1,67 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSink records the records written to it, with the request ID of their context.
+type fakeSink struct {
+	lock       sync.Mutex
+	records    []string
+	requestIDs []string
+	closed     bool
+}
+
+func (s *fakeSink) Write(ctx context.Context, record []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records = append(s.records, string(record))
+	var requestID string
+	if lc, ok := FromContext(ctx); ok {
+		requestID = lc.AwsRequestID
+	}
+	s.requestIDs = append(s.requestIDs, requestID)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestWithSink(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	sink := &fakeSink{}
+	logger := NewLogger(WithSink(sink), WithFunctionARN())
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request", InvokedFunctionArn: "arn:aws:lambda:us-east-1:123456789012:function:test"})
+	logger.InfoContext(ctx, "first")
+	logger.With("service", "orders").WarnContext(ctx, "second")
+
+	require.Len(t, sink.records, 2)
+	assert.Equal(t, []string{"test-request", "test-request"}, sink.requestIDs)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(sink.records[0]), &logOutput))
+	assert.Equal(t, "first", logOutput["message"])
+	assert.Equal(t, "test-request", logOutput["requestId"])
+	assert.Equal(t, "arn:aws:lambda:us-east-1:123456789012:function:test", logOutput["functionArn"])
+
+	require.NoError(t, json.Unmarshal([]byte(sink.records[1]), &logOutput))
+	assert.Equal(t, "second", logOutput["message"])
+	assert.Equal(t, "orders", logOutput["service"])
+
+	assert.NoError(t, Close(logger.Handler()))
+	assert.True(t, sink.closed)
+}
@@ -0,0 +1,74 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// socketDialTimeout bounds how long a write waits to connect to the log socket.
+const socketDialTimeout = 100 * time.Millisecond
+
+// socketWriter writes records to a Unix domain socket, reconnecting after failures
+// and writing to fallback while the socket is unavailable.
+type socketWriter struct {
+	lock     sync.Mutex
+	path     string
+	conn     net.Conn
+	fallback io.Writer
+}
+
+func newSocketWriter(path string, fallback io.Writer) *socketWriter {
+	return &socketWriter{path: path, fallback: fallback}
+}
+
+// Write implements io.Writer.
+func (w *socketWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// retry once with a fresh connection, in case the collector restarted since the last write
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			conn, err := net.DialTimeout("unix", w.path, socketDialTimeout)
+			if err != nil {
+				break
+			}
+			w.conn = conn
+		}
+		if _, err := w.conn.Write(p); err == nil {
+			return len(p), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	return w.fallback.Write(p)
+}
+
+// Close implements io.Closer.
+func (w *socketWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// WithUnixSocket writes records to the Unix domain socket at path, such as one served by a log collector extension.
+// Failed writes are retried on a new connection, and records are written to stdout while the socket is unavailable.
+// Call Close on shutdown, for example from a lambda.WithEnableSIGTERM callback, to close the connection.
+func WithUnixSocket(path string) LogOption {
+	return func(o *logOptions) {
+		o.writer = newSocketWriter(path, os.Stdout)
+	}
+}
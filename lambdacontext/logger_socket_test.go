@@ -0,0 +1,91 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listenUnix starts a Unix socket server at path, sending each line received on the returned channel.
+func listenUnix(t *testing.T, path string) (net.Listener, <-chan string) {
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+	return listener, lines
+}
+
+func TestWithUnixSocket(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	path := filepath.Join(t.TempDir(), "logs.sock")
+	listener, lines := listenUnix(t, path)
+	defer listener.Close()
+
+	logger := NewLogger(WithUnixSocket(path))
+	logger.Info("over the socket")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(<-lines), &logOutput))
+	assert.Equal(t, "over the socket", logOutput["message"])
+	assert.NoError(t, Close(logger.Handler()))
+}
+
+func TestSocketWriter_Reconnect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.sock")
+	listener, lines := listenUnix(t, path)
+	defer listener.Close()
+
+	var fallback bytes.Buffer
+	w := newSocketWriter(path, &fallback)
+	defer w.Close()
+
+	_, err := w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "first", <-lines)
+
+	// simulate the collector dropping the connection
+	w.conn.Close()
+
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", <-lines)
+	assert.Empty(t, fallback.String())
+}
+
+func TestSocketWriter_Fallback(t *testing.T) {
+	var fallback bytes.Buffer
+	w := newSocketWriter(filepath.Join(t.TempDir(), "missing.sock"), &fallback)
+
+	n, err := w.Write([]byte("no collector\n"))
+	require.NoError(t, err)
+	assert.Equal(t, len("no collector\n"), n)
+	assert.Equal(t, "no collector\n", fallback.String())
+	assert.NoError(t, w.Close())
+}
@@ -0,0 +1,17 @@
+//go:build go1.21 && linux
+// +build go1.21,linux
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import "syscall"
+
+// statTmp returns the total and free size in bytes of the file system containing path.
+func statTmp(path string) (total, free uint64, ok bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, false
+	}
+	return stat.Blocks * uint64(stat.Bsize), stat.Bavail * uint64(stat.Bsize), true
+}
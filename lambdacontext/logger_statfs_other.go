@@ -0,0 +1,11 @@
+//go:build go1.21 && !linux
+// +build go1.21,!linux
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+// statTmp is only supported on Linux, where Lambda functions run.
+func statTmp(path string) (total, free uint64, ok bool) {
+	return 0, 0, false
+}
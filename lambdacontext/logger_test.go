@@ -9,8 +9,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
 	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -74,6 +81,12 @@ func TestParseLogLevel(t *testing.T) {
 		{"INFO", "INFO", slog.LevelInfo},
 		{"WARN", "WARN", slog.LevelWarn},
 		{"ERROR", "ERROR", slog.LevelError},
+		{"TRACE", "TRACE", slog.LevelDebug - 4},
+		{"FATAL", "FATAL", slog.LevelError + 4},
+		{"numeric debug", "-4", slog.LevelDebug},
+		{"numeric info", "0", slog.LevelInfo},
+		{"numeric error", "8", slog.LevelError},
+		{"numeric custom", "2", slog.Level(2)},
 		{"empty", "", slog.LevelInfo},
 		{"INVALID", "INVALID", slog.LevelInfo},
 		{"lowercase debug", "debug", slog.LevelInfo},
@@ -88,6 +101,62 @@ func TestParseLogLevel(t *testing.T) {
 	}
 }
 
+func TestEffectiveLevel(t *testing.T) {
+	original := logLevel
+	t.Cleanup(func() { logLevel = original })
+
+	for input, expected := range map[string]slog.Level{"DEBUG": slog.LevelDebug, "ERROR": slog.LevelError, "": slog.LevelInfo} {
+		logLevel = input
+		assert.Equal(t, expected, EffectiveLevel(), input)
+		assert.Equal(t, expected <= slog.LevelDebug, NewLogHandler().Enabled(context.Background(), slog.LevelDebug), input)
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	for name, expected := range map[string]slog.Level{
+		"TRACE": slog.LevelDebug - 4,
+		"DEBUG": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"WARN":  slog.LevelWarn,
+		"ERROR": slog.LevelError,
+		"FATAL": slog.LevelError + 4,
+	} {
+		level, ok := SlogLevel(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, level, name)
+	}
+	for _, name := range []string{"", "info", "VERBOSE"} {
+		_, ok := SlogLevel(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestIsJSONFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"JSON", true},
+		{"Json", true},
+		{"json", true},
+		{"Text", false},
+		{"TEXT", false},
+		{"text", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			withLogFormat(t, tt.input)
+			assert.Equal(t, tt.expected, isJSONFormat())
+
+			var buf bytes.Buffer
+			NewLogger(withWriter(&buf)).Info("test message")
+			assert.Equal(t, tt.expected, json.Valid(buf.Bytes()))
+		})
+	}
+}
+
 func TestLogHandler_JSONFormat(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -397,3 +466,551 @@ func TestNewLogHandler(t *testing.T) {
 	handlerWithOpts := NewLogHandler(WithFunctionARN(), WithTenantID())
 	assert.NotNil(t, handlerWithOpts)
 }
+
+func TestFieldRetryAttempt(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func(context.Context) context.Context
+		expected interface{}
+	}{
+		{
+			name:     "absent",
+			ctx:      func(ctx context.Context) context.Context { return ctx },
+			expected: nil,
+		},
+		{
+			name:     "from context",
+			ctx:      func(ctx context.Context) context.Context { return WithRetryAttempt(ctx, 2) },
+			expected: float64(2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			handler := &lambdaHandler{
+				handler: slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: ReplaceAttr}),
+				fields:  []Field{FieldRetryAttempt()},
+			}
+
+			ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+			slog.New(handler).InfoContext(tt.ctx(ctx), "test message")
+
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+			if tt.expected == nil {
+				assert.NotContains(t, logOutput, "retryAttempt")
+			} else {
+				assert.Equal(t, tt.expected, logOutput["retryAttempt"])
+			}
+		})
+	}
+}
+
+func TestRetryAttemptFromContext(t *testing.T) {
+	_, ok := RetryAttemptFromContext(context.Background())
+	assert.False(t, ok)
+
+	lc := &LambdaContext{ClientContext: ClientContext{Custom: map[string]string{"retryAttempt": "1"}}}
+	attempt, ok := RetryAttemptFromContext(NewContext(context.Background(), lc))
+	assert.True(t, ok)
+	assert.Equal(t, 1, attempt)
+
+	attempt, ok = RetryAttemptFromContext(WithRetryAttempt(NewContext(context.Background(), lc), 3))
+	assert.True(t, ok)
+	assert.Equal(t, 3, attempt)
+}
+
+// withWriter directs the handler's output to w.
+func withWriter(w io.Writer) LogOption {
+	return func(o *logOptions) {
+		o.writer = w
+	}
+}
+
+// withLogFormat sets the log format for the duration of the test.
+func withLogFormat(t *testing.T, format string) {
+	original := logFormat
+	logFormat = format
+	t.Cleanup(func() { logFormat = original })
+}
+
+func TestWithMessageAsArray(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithMessageAsArray(), withWriter(&buf))
+	logger.Info("first line\nsecond line")
+	logger.Info("single line")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var multi, single map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &multi))
+	require.NoError(t, json.Unmarshal(lines[1], &single))
+
+	assert.Equal(t, []interface{}{"first line", "second line"}, multi["message"])
+	assert.Equal(t, "single line", single["message"])
+}
+
+func TestMessageDefaultsToString(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	NewLogger(withWriter(&buf)).Info("first line\nsecond line")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "first line\nsecond line", logOutput["message"])
+}
+
+func TestWithMessageKey(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	for _, key := range []string{"msg", "body"} {
+		t.Run(key, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewLogger(WithMessageKey(key), withWriter(&buf)).Info("hello", "user", "alice")
+
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+			assert.Equal(t, "hello", logOutput[key])
+			assert.NotContains(t, logOutput, "message")
+			assert.Equal(t, "alice", logOutput["user"])
+		})
+	}
+}
+
+func TestWithEmptyMessagePolicy(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	tests := []struct {
+		name     string
+		policy   EmptyMessagePolicy
+		expected interface{} // nil when the message key is omitted
+	}{
+		{"keep", EmptyMessageKeep, ""},
+		{"omit", EmptyMessageOmit, nil},
+		{"placeholder", EmptyMessagePlaceholder, "(no message)"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLogger(WithEmptyMessagePolicy(test.policy), withWriter(&buf))
+			logger.Info("")
+			logger.Info("not empty")
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			require.Len(t, lines, 2)
+			var empty, notEmpty map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &empty))
+			require.NoError(t, json.Unmarshal([]byte(lines[1]), &notEmpty))
+			if test.expected == nil {
+				assert.NotContains(t, empty, "message")
+			} else {
+				assert.Equal(t, test.expected, empty["message"])
+			}
+			assert.Equal(t, "INFO", empty["level"])
+			assert.Equal(t, "not empty", notEmpty["message"])
+		})
+	}
+}
+
+func TestWithRequestIDKey(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithRequestIDKey("aws.requestId"), withWriter(&buf))
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "lambda-request-id"})
+	logger.InfoContext(ctx, "request handled", "requestId", "framework-request-id")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "lambda-request-id", logOutput["aws.requestId"])
+	assert.Equal(t, "framework-request-id", logOutput["requestId"])
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"requestId"`)))
+}
+
+func TestWithFieldSetSelector(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	sets := map[string]FieldSet{
+		"events.SQSEvent":               {FieldEventSourceARN(), FieldRetryAttempt()},
+		"events.APIGatewayProxyRequest": {FieldParentRequestID()},
+	}
+	var buf bytes.Buffer
+	logger := NewLogger(WithFunctionARN(), WithFieldSetSelector(func(ctx context.Context) []Field {
+		eventType, _ := EventTypeFromContext(ctx)
+		return sets[eventType]
+	}), withWriter(&buf))
+
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request", InvokedFunctionArn: "arn"})
+	ctx = WithRetryAttempt(WithParentRequestID(ctx, "parent-request"), 1)
+	ctx = WithEventSourceARN(ctx, "arn:aws:sqs:us-east-1:123456789012:queue")
+	logger.InfoContext(WithEventType(ctx, "events.SQSEvent"), "sqs")
+	logger.InfoContext(WithEventType(ctx, "events.APIGatewayProxyRequest"), "api")
+	logger.InfoContext(ctx, "unknown")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	var sqs, api, unknown map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &sqs))
+	require.NoError(t, json.Unmarshal(lines[1], &api))
+	require.NoError(t, json.Unmarshal(lines[2], &unknown))
+
+	assert.Equal(t, "arn:aws:sqs:us-east-1:123456789012:queue", sqs["eventSourceArn"])
+	assert.Equal(t, float64(1), sqs["retryAttempt"])
+	assert.NotContains(t, sqs, "parentRequestId")
+
+	assert.Equal(t, "parent-request", api["parentRequestId"])
+	assert.NotContains(t, api, "eventSourceArn")
+	assert.NotContains(t, api, "retryAttempt")
+
+	for _, logOutput := range []map[string]interface{}{sqs, api, unknown} {
+		assert.Equal(t, "arn", logOutput["functionArn"])
+	}
+	assert.NotContains(t, unknown, "eventSourceArn")
+	assert.NotContains(t, unknown, "parentRequestId")
+}
+
+func TestWithAttrCount(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithAttrCount(), withWriter(&buf)).With("service", "orders")
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request-id"})
+	logger.InfoContext(ctx, "order processed", "orderId", "order-123", slog.Group("customer", "id", 7, "tier", "gold"))
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	// service, orderId, customer and requestId
+	assert.Equal(t, float64(4), logOutput["attrCount"])
+}
+
+func TestWithVerbosityFlag(t *testing.T) {
+	withLogFormat(t, "JSON")
+	original := logLevel
+	t.Cleanup(func() { logLevel = original })
+
+	for level, expected := range map[string]bool{"DEBUG": true, "INFO": false, "TRACE": true, "WARN": false} {
+		t.Run(level, func(t *testing.T) {
+			logLevel = level
+			var buf bytes.Buffer
+			NewLogger(WithVerbosityFlag(), withWriter(&buf)).Warn("verbosity")
+
+			var logOutput map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+			assert.Equal(t, expected, logOutput["debugEnabled"])
+		})
+	}
+}
+
+func TestWithDeadlineWarning(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithDeadlineWarning(time.Second), withWriter(&buf))
+
+	near, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	far, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	logger.InfoContext(near, "near")
+	logger.ErrorContext(near, "near error")
+	logger.InfoContext(far, "far")
+	logger.Info("no deadline")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	var records []map[string]interface{}
+	for _, line := range lines {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &logOutput))
+		records = append(records, logOutput)
+	}
+	assert.Equal(t, "WARN", records[0]["level"])
+	assert.Equal(t, true, records[0]["nearTimeout"])
+	assert.Equal(t, "ERROR", records[1]["level"])
+	assert.Equal(t, true, records[1]["nearTimeout"])
+	for _, record := range records[2:] {
+		assert.Equal(t, "INFO", record["level"])
+		assert.NotContains(t, record, "nearTimeout")
+	}
+}
+
+// recordingHandler is a slog.Handler that records the attrs of each handled record.
+type recordingHandler struct {
+	attrs   []slog.Attr
+	records *[]map[string]slog.Value
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	record := map[string]slog.Value{}
+	for _, attr := range h.attrs {
+		record[attr.Key] = attr.Value
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		record[attr.Key] = attr.Value
+		return true
+	})
+	*h.records = append(*h.records, record)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recordingHandler{attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...), records: h.records}
+}
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func TestInject(t *testing.T) {
+	var records []map[string]slog.Value
+	logger := slog.New(Inject(&recordingHandler{records: &records}, FieldRetryAttempt()))
+
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request-id"})
+	logger.With("service", "orders").InfoContext(WithRetryAttempt(ctx, 2), "hello")
+	logger.Info("no context")
+
+	require.Len(t, records, 2)
+	assert.Equal(t, "test-request-id", records[0]["requestId"].String())
+	assert.Equal(t, int64(2), records[0]["retryAttempt"].Int64())
+	assert.Equal(t, "orders", records[0]["service"].String())
+	assert.NotContains(t, records[1], "requestId")
+	assert.Equal(t, []string{"retryAttempt"}, HandlerFields(logger.Handler()))
+}
+
+func TestSaveAndRestoreDefault(t *testing.T) {
+	original, originalWriter := slog.Default(), log.Writer()
+	restore := SaveAndRestoreDefault()
+
+	var buf bytes.Buffer
+	slog.SetDefault(NewLogger(withWriter(&buf)))
+	assert.NotEqual(t, original, slog.Default())
+	log.Print("via the standard library logger")
+	assert.Contains(t, buf.String(), "via the standard library logger")
+
+	restore()
+	assert.Equal(t, original, slog.Default())
+	assert.Equal(t, originalWriter, log.Writer())
+}
+
+func TestHandlerFields(t *testing.T) {
+	assert.Empty(t, HandlerFields(NewLogHandler()))
+	assert.Equal(t, []string{"functionArn", "tenantId", "retryAttempt"},
+		HandlerFields(NewLogHandler(WithFunctionARN(), WithTenantID(), WithFields(FieldRetryAttempt()))))
+	assert.Equal(t, []string{"functionArn"}, HandlerFields(NewLogHandler(WithFunctionARN()).WithGroup("app")))
+	assert.Nil(t, HandlerFields(slog.NewJSONHandler(io.Discard, nil)))
+}
+
+func TestWithTimezoneField(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	NewLogger(WithTimezoneField(), withWriter(&buf)).Info("in UTC")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "UTC", logOutput["tz"])
+	assert.True(t, strings.HasSuffix(logOutput["timestamp"].(string), "Z"), logOutput["timestamp"])
+}
+
+func TestWithTimezone(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	NewLogger(WithTimezone(time.FixedZone("UTC+2", 2*60*60)), withWriter(&buf)).Info("in UTC+2")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "UTC+2", logOutput["tz"])
+	assert.True(t, strings.HasSuffix(logOutput["timestamp"].(string), "+02:00"), logOutput["timestamp"])
+}
+
+func TestWithSchemaVersion(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	NewLogger(WithSchemaVersion("2"), withWriter(&buf)).Info("outside invocation")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	assert.Equal(t, "2", logOutput["schemaVersion"])
+
+	buf.Reset()
+	NewLogger(withWriter(&buf)).Info("no version")
+
+	var defaultOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &defaultOutput))
+	assert.NotContains(t, defaultOutput, "schemaVersion")
+}
+
+func TestWithSuppressPattern(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(
+		WithSuppressPattern(regexp.MustCompile(`^connection pool: idle`)),
+		WithSuppressPattern(regexp.MustCompile(`deprecated`)),
+		withWriter(&buf),
+	)
+	logger.Info("connection pool: idle connection closed")
+	logger.Error("this API is deprecated")
+	logger.Info("order processed")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 1)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &logOutput))
+	assert.Equal(t, "order processed", logOutput["message"])
+}
+
+func TestWithConditionalLogging(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	type debugKey struct{}
+	var buf bytes.Buffer
+	logger := NewLogger(WithConditionalLogging(func(ctx context.Context) bool {
+		return ctx.Value(debugKey{}) == true
+	}), withWriter(&buf))
+
+	flagged := context.WithValue(context.Background(), debugKey{}, true)
+	logger.InfoContext(flagged, "flagged info")
+	logger.WarnContext(flagged, "flagged warn")
+	logger.InfoContext(context.Background(), "unflagged info")
+	logger.WarnContext(context.Background(), "unflagged warn")
+	logger.ErrorContext(context.Background(), "unflagged error")
+
+	var messages []interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		messages = append(messages, logOutput["message"])
+	}
+	assert.Equal(t, []interface{}{"flagged info", "flagged warn", "unflagged error"}, messages)
+}
+
+func TestWithSuppressInit(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithSuppressInit(), withWriter(&buf))
+	logger.Info("init info")
+	logger.Error("init error")
+
+	ctx := NewContext(context.Background(), &LambdaContext{AwsRequestID: "test-request"})
+	InvokeStarted(ctx)
+	logger.InfoContext(ctx, "invocation info")
+	InvokeEnded(ctx)
+	logger.Info("post-init info")
+
+	var messages []interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		messages = append(messages, logOutput["message"])
+	}
+	assert.Equal(t, []interface{}{"init error", "invocation info", "post-init info"}, messages)
+}
+
+func TestWithSuppressInit_CloseUnregistersHook(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	registered := len(invokeStartHooks)
+	handler := NewLogHandler(WithSuppressInit(), withWriter(&bytes.Buffer{}))
+	assert.Len(t, invokeStartHooks, registered+1)
+
+	require.NoError(t, Close(handler))
+	assert.Len(t, invokeStartHooks, registered)
+}
+
+func TestWithFieldToggle(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	enabled := false
+	logger := NewLogger(
+		WithFunctionARN(),
+		WithTenantID(),
+		WithFieldToggle("functionArn", func() bool { return enabled }),
+		withWriter(&buf),
+	)
+
+	lc := &LambdaContext{AwsRequestID: "test-request", InvokedFunctionArn: "test-arn", TenantID: "test-tenant"}
+	ctx := NewContext(context.Background(), lc)
+	logger.InfoContext(ctx, "disabled")
+	enabled = true
+	logger.InfoContext(ctx, "enabled")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var disabled, enabledOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &disabled))
+	require.NoError(t, json.Unmarshal(lines[1], &enabledOutput))
+
+	assert.NotContains(t, disabled, "functionArn")
+	assert.Equal(t, "test-tenant", disabled["tenantId"])
+	assert.Equal(t, "test-arn", enabledOutput["functionArn"])
+	assert.Equal(t, "test-tenant", enabledOutput["tenantId"])
+}
+
+func TestLogHandler_CachedAttrsFollowContext(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithFunctionARN(), withWriter(&buf))
+
+	ctx1 := NewContext(context.Background(), &LambdaContext{AwsRequestID: "request-aaa", InvokedFunctionArn: "arn-aaa"})
+	ctx2 := NewContext(context.Background(), &LambdaContext{AwsRequestID: "request-bbb", InvokedFunctionArn: "arn-bbb"})
+
+	logger.InfoContext(ctx1, "message 1")
+	logger.InfoContext(ctx1, "message 2")
+	logger.InfoContext(ctx2, "message 3")
+	logger.InfoContext(ctx1, "message 4")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 4)
+
+	expected := []string{"aaa", "aaa", "bbb", "aaa"}
+	for i, line := range lines {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		assert.Equal(t, "request-"+expected[i], logOutput["requestId"])
+		assert.Equal(t, "arn-"+expected[i], logOutput["functionArn"])
+	}
+}
+
+func BenchmarkLogHandler_SameContext(b *testing.B) {
+	lc := &LambdaContext{
+		AwsRequestID:       "test-request-123",
+		InvokedFunctionArn: "arn:aws:lambda:us-east-1:123456789:function:test",
+		TenantID:           "tenant-abc",
+	}
+	ctx := NewContext(context.Background(), lc)
+
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cached=%v", cached), func(b *testing.B) {
+			options := &logOptions{}
+			WithFunctionARN()(options)
+			WithTenantID()(options)
+			handler := &lambdaHandler{
+				handler: slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{ReplaceAttr: ReplaceAttr}),
+				fields:  options.fields,
+			}
+			if cached {
+				handler.cache = &atomic.Pointer[cachedAttrs]{}
+			}
+			logger := slog.New(handler)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.InfoContext(ctx, "test message")
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet ULIDs are encoded with.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidGenerator generates ULIDs that increase monotonically, even when several are generated
+// in the same millisecond or the clock moves backwards.
+type ulidGenerator struct {
+	lock    sync.Mutex
+	ms      uint64 // timestamp of the last ULID
+	entropy [10]byte
+}
+
+// recordIDs generates the ULIDs of WithULID, shared by all handlers so that they are monotonic within the process.
+var recordIDs = &ulidGenerator{}
+
+// WithULID includes a ULID identifying each record as recordId, for downstream systems that order or deduplicate
+// records. The ULIDs of the records of the process increase monotonically: records logged in the same millisecond
+// get the random component of the previous ULID incremented by one, following the ULID specification.
+//
+// See https://github.com/ulid/spec
+func WithULID() LogOption {
+	return WithFields(Field{key: "recordId", attr: func(context.Context) (slog.Value, bool) {
+		id, ok := recordIDs.next(time.Now())
+		return slog.StringValue(id), ok
+	}})
+}
+
+// next returns the ULID following the last one generated, for a record logged at t.
+func (g *ulidGenerator) next(t time.Time) (string, bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	ms := uint64(t.UnixMilli())
+	if ms > g.ms {
+		if _, err := rand.Read(g.entropy[:]); err != nil {
+			return "", false
+		}
+		g.ms = ms
+	} else if !increment(g.entropy[:]) {
+		// the random component overflowed, so the ULID moves on to the next millisecond
+		g.ms++
+	}
+
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], g.ms<<16)
+	copy(id[6:], g.entropy[:])
+	return encodeULID(id), true
+}
+
+// increment adds one to the big-endian integer b, reporting false if it overflowed.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID encodes the 128 bits of id as 26 Crockford base32 characters, the first holding the 3 highest bits.
+func encodeULID(id [16]byte) string {
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	var s [26]byte
+	for i := len(s) - 1; i >= 0; i-- {
+		s[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
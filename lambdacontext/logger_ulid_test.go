@@ -0,0 +1,65 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithULID(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithULID(), withWriter(&buf))
+	for i := 0; i < 1000; i++ {
+		logger.Info("rapid")
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 1000)
+	seen := map[string]bool{}
+	var previous string
+	for _, line := range lines {
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &logOutput))
+		id, ok := logOutput["recordId"].(string)
+		require.True(t, ok)
+		assert.Regexp(t, `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`, id)
+		assert.False(t, seen[id], "duplicate ULID %s", id)
+		assert.Greater(t, id, previous)
+		seen[id] = true
+		previous = id
+	}
+}
+
+func TestULIDGenerator(t *testing.T) {
+	g := &ulidGenerator{}
+	now := time.UnixMilli(1469918176385)
+
+	first, ok := g.next(now)
+	require.True(t, ok)
+	assert.Equal(t, "01ARYZ6S41", first[:10], "the first 10 characters encode the timestamp")
+
+	// the clock moving backwards does not break monotonicity
+	second, _ := g.next(now.Add(-time.Second))
+	assert.Greater(t, second, first)
+	assert.Equal(t, first[:10], second[:10])
+
+	// overflowing the random component moves on to the next millisecond
+	for i := range g.entropy {
+		g.entropy[i] = 0xff
+	}
+	third, _ := g.next(now)
+	assert.Greater(t, third, second)
+	assert.Equal(t, "01ARYZ6S42", third[:10])
+	assert.Equal(t, "0000000000000000", third[10:])
+}
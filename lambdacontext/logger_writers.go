@@ -0,0 +1,119 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// recordSizeWriter appends the size of each record written to it as recordBytes.
+type recordSizeWriter struct {
+	out  io.Writer
+	json bool
+}
+
+// Write implements io.Writer.
+func (w *recordSizeWriter) Write(p []byte) (int, error) {
+	record := bytes.TrimRight(p, "\n")
+	size := strconv.Itoa(len(record))
+
+	var buf bytes.Buffer
+	if w.json && bytes.HasSuffix(record, []byte("}")) {
+		buf.Write(record[:len(record)-1])
+		buf.WriteString(`,"recordBytes":` + size + "}\n")
+	} else {
+		buf.Write(record)
+		buf.WriteString(" recordBytes=" + size + "\n")
+	}
+	if _, err := w.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// checksumWriter appends an HMAC-SHA256 of each record written to it as _hmac.
+type checksumWriter struct {
+	out  io.Writer
+	key  []byte
+	json bool
+}
+
+// Write implements io.Writer.
+func (w *checksumWriter) Write(p []byte) (int, error) {
+	record := bytes.TrimRight(p, "\n")
+	mac := hmac.New(sha256.New, w.key)
+	mac.Write(record)
+	sum := hex.EncodeToString(mac.Sum(nil))
+
+	var buf bytes.Buffer
+	if w.json && bytes.HasSuffix(record, []byte("}")) {
+		buf.Write(record[:len(record)-1])
+		buf.WriteString(`,"_hmac":"` + sum + "\"}\n")
+	} else {
+		buf.Write(record)
+		buf.WriteString(" _hmac=" + sum + "\n")
+	}
+	if _, err := w.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// promotedKeysWriter copies the given keys from nested objects to the top level of each JSON record written to it.
+type promotedKeysWriter struct {
+	out  io.Writer
+	keys []string
+}
+
+// Write implements io.Writer. Records that fail to decode are written unchanged.
+func (w *promotedKeysWriter) Write(p []byte) (int, error) {
+	members, ok, err := decodeObject(bytes.TrimRight(p, "\n"))
+	if err != nil || !ok {
+		return w.out.Write(p)
+	}
+	for _, key := range w.keys {
+		if slices.ContainsFunc(members, func(m jsonMember) bool { return m.key == key }) {
+			continue
+		}
+		if value, ok := findNestedKey(members, key); ok {
+			members = append(members, jsonMember{key: key, value: value})
+		}
+	}
+	record, err := encodeObject(members)
+	if err != nil {
+		return w.out.Write(p)
+	}
+	if _, err := w.out.Write(append(record, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// findNestedKey returns the value of the first occurrence of key within the objects nested in members, depth first.
+func findNestedKey(members []jsonMember, key string) (json.RawMessage, bool) {
+	for _, m := range members {
+		nested, ok, err := decodeObject(m.value)
+		if err != nil || !ok {
+			continue
+		}
+		for _, n := range nested {
+			if n.key == key {
+				return n.value, true
+			}
+		}
+		if value, ok := findNestedKey(nested, key); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
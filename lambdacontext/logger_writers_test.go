@@ -0,0 +1,106 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRecordSize(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	NewLogger(WithRecordSize(), withWriter(&buf)).Info("test message", "key", "value")
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+	require.Contains(t, logOutput, "recordBytes")
+
+	size := int(logOutput["recordBytes"].(float64))
+	original := strings.Replace(strings.TrimSuffix(buf.String(), "\n"), `,"recordBytes":`+strconv.Itoa(size), "", 1)
+	assert.Equal(t, len(original), size)
+}
+
+func TestWithRecordSize_Text(t *testing.T) {
+	withLogFormat(t, "Text")
+
+	var buf bytes.Buffer
+	NewLogger(WithRecordSize(), withWriter(&buf)).Info("test message")
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	i := strings.LastIndex(line, " recordBytes=")
+	require.Greater(t, i, 0, line)
+	assert.Equal(t, strconv.Itoa(i), line[i+len(" recordBytes="):])
+}
+
+func TestWithChecksum(t *testing.T) {
+	key := []byte("secret")
+	hmacOf := func(record string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(record))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		withLogFormat(t, "JSON")
+
+		var buf bytes.Buffer
+		NewLogger(WithChecksum(key), WithRecordSize(), withWriter(&buf)).Info("audit event", "user", "alice")
+
+		var logOutput map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+		sum, ok := logOutput["_hmac"].(string)
+		require.True(t, ok)
+		original := strings.Replace(strings.TrimSuffix(buf.String(), "\n"), `,"_hmac":"`+sum+`"`, "", 1)
+		assert.Contains(t, original, "recordBytes")
+		assert.Equal(t, hmacOf(original), sum)
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		withLogFormat(t, "Text")
+
+		var buf bytes.Buffer
+		NewLogger(WithChecksum(key), withWriter(&buf)).Info("audit event")
+
+		line := strings.TrimSuffix(buf.String(), "\n")
+		i := strings.LastIndex(line, " _hmac=")
+		require.Greater(t, i, 0, line)
+		assert.Equal(t, hmacOf(line[:i]), line[i+len(" _hmac="):])
+	})
+}
+
+func TestWithPromotedKeys(t *testing.T) {
+	withLogFormat(t, "JSON")
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithPromotedKeys("userId", "orderId", "missing"), withWriter(&buf))
+	logger.WithGroup("app").Info("test message",
+		slog.Group("request", slog.String("userId", "user-123")),
+		slog.String("orderId", "order-456"),
+	)
+
+	var logOutput map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logOutput))
+
+	assert.Equal(t, "user-123", logOutput["userId"])
+	assert.Equal(t, "order-456", logOutput["orderId"])
+	assert.NotContains(t, logOutput, "missing")
+
+	app := logOutput["app"].(map[string]interface{})
+	assert.Equal(t, "order-456", app["orderId"])
+	assert.Equal(t, "user-123", app["request"].(map[string]interface{})["userId"])
+}
@@ -0,0 +1,142 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// xrayDaemonHeader precedes each document sent to the X-Ray daemon.
+const xrayDaemonHeader = `{"format": "json", "version": 1}` + "\n"
+
+// xrayAnnotator records attrs as annotations on X-Ray subsegments, sent to the X-Ray daemon over UDP.
+type xrayAnnotator struct {
+	address string
+	keys    []string
+}
+
+// WithXRayAnnotations also records the attrs with the given keys as annotations on the invocation's X-Ray trace.
+// Each record with matching attrs is sent to the X-Ray daemon at AWS_XRAY_DAEMON_ADDRESS as a subsegment named "log".
+// Nothing is sent when the daemon address is unset or the invocation is not sampled, and errors are ignored.
+func WithXRayAnnotations(keys ...string) LogOption {
+	return func(o *logOptions) {
+		if address := xrayDaemonAddress(os.Getenv("AWS_XRAY_DAEMON_ADDRESS")); address != "" {
+			o.xray = &xrayAnnotator{address: address, keys: keys}
+		}
+	}
+}
+
+// xrayDaemonAddress returns the UDP address from an AWS_XRAY_DAEMON_ADDRESS value,
+// which is either "host:port" or "tcp:host:port udp:host:port".
+func xrayDaemonAddress(s string) string {
+	for _, part := range strings.Fields(s) {
+		if address, ok := strings.CutPrefix(part, "udp:"); ok {
+			return address
+		}
+		if !strings.HasPrefix(part, "tcp:") {
+			return part
+		}
+	}
+	return ""
+}
+
+// annotate sends the attrs of r matching the configured keys as annotations on a subsegment of the current trace.
+func (x *xrayAnnotator) annotate(ctx context.Context, r slog.Record) {
+	annotations := map[string]interface{}{}
+	r.Attrs(func(attr slog.Attr) bool {
+		if slices.Contains(x.keys, attr.Key) {
+			if v, ok := annotationValue(attr.Value.Resolve()); ok {
+				annotations[attr.Key] = v
+			}
+		}
+		return true
+	})
+	if len(annotations) == 0 {
+		return
+	}
+
+	traceID, parentID, sampled := parseTraceHeader(traceHeader(ctx))
+	if traceID == "" || parentID == "" || !sampled {
+		return
+	}
+
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return
+	}
+	now := float64(time.Now().UnixNano()) / float64(time.Second)
+	segment, err := json.Marshal(map[string]interface{}{
+		"name":        "log",
+		"id":          hex.EncodeToString(id[:]),
+		"trace_id":    traceID,
+		"parent_id":   parentID,
+		"type":        "subsegment",
+		"start_time":  now,
+		"end_time":    now,
+		"annotations": annotations,
+	})
+	if err != nil {
+		return
+	}
+
+	conn, err := net.Dial("udp", x.address)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	_, _ = conn.Write(append([]byte(xrayDaemonHeader), segment...))
+}
+
+// annotationValue converts v to a string, number or boolean, the only annotation types supported by X-Ray.
+func annotationValue(v slog.Value) (interface{}, bool) {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String(), true
+	case slog.KindInt64:
+		return v.Int64(), true
+	case slog.KindUint64:
+		return v.Uint64(), true
+	case slog.KindFloat64:
+		return v.Float64(), true
+	case slog.KindBool:
+		return v.Bool(), true
+	default:
+		return nil, false
+	}
+}
+
+// traceHeader returns the X-Ray trace header of the invocation, as set by the lambda package.
+func traceHeader(ctx context.Context) string {
+	if header, ok := ctx.Value("x-amzn-trace-id").(string); ok && header != "" {
+		return header
+	}
+	return os.Getenv("_X_AMZN_TRACE_ID")
+}
+
+// parseTraceHeader parses a trace header of the form "Root=1-...;Parent=...;Sampled=1".
+func parseTraceHeader(header string) (traceID, parentID string, sampled bool) {
+	for _, part := range strings.Split(header, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "Root":
+			traceID = value
+		case "Parent":
+			parentID = value
+		case "Sampled":
+			sampled = value == "1"
+		}
+	}
+	return traceID, parentID, sampled
+}
@@ -0,0 +1,79 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithXRayAnnotations(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	t.Setenv("AWS_XRAY_DAEMON_ADDRESS", listener.LocalAddr().String())
+
+	logger := NewLogger(WithXRayAnnotations("orderId", "amount"), withWriter(io.Discard))
+
+	//nolint:staticcheck
+	ctx := context.WithValue(context.Background(), "x-amzn-trace-id", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+	logger.InfoContext(ctx, "order processed", "orderId", "order-123", "amount", 42, "ignored", "value")
+
+	packet := make([]byte, 4096)
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := listener.ReadFrom(packet)
+	require.NoError(t, err)
+
+	header, body, found := bytes.Cut(packet[:n], []byte("\n"))
+	require.True(t, found)
+	assert.JSONEq(t, `{"format": "json", "version": 1}`, string(header))
+
+	var segment map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &segment))
+	assert.Equal(t, "subsegment", segment["type"])
+	assert.Equal(t, "1-5759e988-bd862e3fe1be46a994272793", segment["trace_id"])
+	assert.Equal(t, "53995c3f42cd8ad8", segment["parent_id"])
+	assert.Equal(t, map[string]interface{}{"orderId": "order-123", "amount": float64(42)}, segment["annotations"])
+}
+
+func TestWithXRayAnnotations_NotSampled(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	t.Setenv("AWS_XRAY_DAEMON_ADDRESS", listener.LocalAddr().String())
+
+	logger := NewLogger(WithXRayAnnotations("orderId"), withWriter(io.Discard))
+
+	//nolint:staticcheck
+	ctx := context.WithValue(context.Background(), "x-amzn-trace-id", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=0")
+	logger.InfoContext(ctx, "order processed", "orderId", "order-123")
+
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, _, err = listener.ReadFrom(make([]byte, 4096))
+	assert.Error(t, err)
+}
+
+func TestWithXRayAnnotations_NoDaemon(t *testing.T) {
+	t.Setenv("AWS_XRAY_DAEMON_ADDRESS", "")
+	handler := NewLogHandler(WithXRayAnnotations("orderId"), withWriter(io.Discard))
+	assert.Nil(t, handler.(*lambdaHandler).xray)
+	assert.NotPanics(t, func() { slog.New(handler).Info("message", "orderId", "order-123") })
+}
+
+func TestXRayDaemonAddress(t *testing.T) {
+	assert.Equal(t, "127.0.0.1:2000", xrayDaemonAddress("127.0.0.1:2000"))
+	assert.Equal(t, "127.0.0.2:2000", xrayDaemonAddress("tcp:127.0.0.1:2000 udp:127.0.0.2:2000"))
+	assert.Equal(t, "", xrayDaemonAddress(""))
+}
@@ -0,0 +1,269 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package msgpack provides a [slog.Handler] that writes records as MessagePack for AWS Lambda functions,
+// for log pipelines that ingest a binary format to reduce the size and parsing cost of records.
+//
+// Each record is written as a MessagePack map with the keys timestamp, level and message, in a single write.
+// The timestamp uses the MessagePack timestamp extension type. Attributes become entries of the map,
+// with groups as nested maps, and the requestId of the Lambda context is injected as requestId.
+// As MessagePack values are self-delimiting, records are written back to back without separators.
+//
+// See https://github.com/msgpack/msgpack/blob/master/spec.md
+package msgpack
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// reservedFields are the top-level keys written by Handler, which attributes cannot replace.
+var reservedFields = map[string]bool{"timestamp": true, "level": true, "message": true, "requestId": true}
+
+// field is an attribute added with WithAttrs, with the groups it was added within.
+type field struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// Handler is a [slog.Handler] that writes MessagePack records to an io.Writer.
+type Handler struct {
+	opts   slog.HandlerOptions
+	lock   *sync.Mutex
+	w      io.Writer
+	groups []string
+	fields []field
+}
+
+// NewHandler returns a Handler writing to w, using the given options, or the defaults if opts is nil.
+func NewHandler(w io.Writer, opts *slog.HandlerOptions) *Handler {
+	h := &Handler{lock: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	record := map[string]interface{}{}
+	for _, f := range h.fields {
+		h.addAttr(record, f.groups, f.attr)
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		h.addAttr(record, h.groups, attr)
+		return true
+	})
+
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	record["timestamp"] = t
+	record["level"] = r.Level.String()
+	record["message"] = r.Message
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		record["requestId"] = lc.AwsRequestID
+	}
+
+	b := appendValue(nil, record)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	_, err := h.w.Write(b)
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.fields = h.fields[:len(h.fields):len(h.fields)]
+	for _, attr := range attrs {
+		clone.fields = append(clone.fields, field{groups: h.groups, attr: attr})
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+// addAttr adds attr to record within the nested maps of groups. Attributes replacing reserved fields are dropped.
+func (h *Handler) addAttr(record map[string]interface{}, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, a := range attr.Value.Group() {
+			h.addAttr(record, groups, a)
+		}
+		return
+	}
+	if (len(groups) == 0 && reservedFields[attr.Key]) || (len(groups) > 0 && reservedFields[groups[0]]) {
+		return
+	}
+	object := record
+	for _, group := range groups {
+		nested, ok := object[group].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			object[group] = nested
+		}
+		object = nested
+	}
+	object[attr.Key] = fieldValue(attr.Value)
+}
+
+// fieldValue converts v to a value encoded by appendValue.
+func fieldValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return v.Any()
+	default:
+		return v.Any()
+	}
+}
+
+// appendValue appends the MessagePack encoding of v to b. Values of types without a MessagePack
+// equivalent are encoded as their JSON representation decoded into maps, arrays and scalars,
+// or as their fmt representation if they cannot be marshaled as JSON.
+func appendValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case int:
+		return appendInt(b, int64(v))
+	case int64:
+		return appendInt(b, v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			return appendInt(b, int64(v))
+		}
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+	case float64:
+		return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(v))
+	case string:
+		return appendString(b, v)
+	case []byte:
+		return append(appendLength(b, len(v), 0, 0, 0xc4, 0xc5, 0xc6), v...)
+	case time.Time:
+		return appendTime(b, v)
+	case []interface{}:
+		b = appendLength(b, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, e := range v {
+			b = appendValue(b, e)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = appendLength(b, len(v), 0x80, 16, 0, 0xde, 0xdf)
+		for _, k := range keys {
+			b = appendValue(appendString(b, k), v[k])
+		}
+		return b
+	default:
+		var decoded interface{}
+		if encoded, err := json.Marshal(v); err == nil && json.Unmarshal(encoded, &decoded) == nil {
+			return appendValue(b, decoded)
+		}
+		return appendString(b, fmt.Sprint(v))
+	}
+}
+
+// appendInt appends the shortest MessagePack encoding of n to b.
+func appendInt(b []byte, n int64) []byte {
+	switch {
+	case n >= -32 && n <= math.MaxInt8: // positive and negative fixint
+		return append(b, byte(n))
+	case n >= math.MinInt8 && n <= math.MaxInt8:
+		return append(b, 0xd0, byte(n))
+	case n >= math.MinInt16 && n <= math.MaxInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(n))
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(n))
+	}
+}
+
+// appendString appends the MessagePack encoding of s to b.
+func appendString(b []byte, s string) []byte {
+	return append(appendLength(b, len(s), 0xa0, 32, 0xd9, 0xda, 0xdb), s...)
+}
+
+// appendLength appends the header of a string, binary, array or map of length n to b: the fix format
+// holding n in its low bits when n is below fixLimit, or the 8, 16 or 32-bit length format.
+// fixLimit and len8 are zero for the types without these formats.
+func appendLength(b []byte, n int, fixed byte, fixLimit int, len8, len16, len32 byte) []byte {
+	switch {
+	case n < fixLimit:
+		return append(b, fixed|byte(n))
+	case len8 != 0 && n <= math.MaxUint8:
+		return append(b, len8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, len16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, len32), uint32(n))
+	}
+}
+
+// appendTime appends t to b as the MessagePack timestamp extension type, in its 64-bit format
+// when the seconds fit in 34 bits, and in its 96-bit format otherwise.
+func appendTime(b []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	if sec >= 0 && sec < 1<<34 {
+		return binary.BigEndian.AppendUint64(append(b, 0xd7, 0xff), nsec<<34|uint64(sec))
+	}
+	b = binary.BigEndian.AppendUint32(append(b, 0xc7, 12, 0xff), uint32(nsec))
+	return binary.BigEndian.AppendUint64(b, uint64(sec))
+}
@@ -0,0 +1,220 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package msgpack
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decode decodes the MessagePack value at the start of b, returning it and the remaining bytes.
+// Integers are decoded as int64, except for uint64 values above math.MaxInt64.
+func decode(b []byte) (interface{}, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errors.New("unexpected end of input")
+	}
+	c, b := b[0], b[1:]
+	switch {
+	case c <= 0x7f:
+		return int64(c), b, nil
+	case c >= 0xe0:
+		return int64(int8(c)), b, nil
+	case c&0xf0 == 0x80:
+		return decodeMap(b, int(c&0x0f))
+	case c&0xf0 == 0x90:
+		return decodeArray(b, int(c&0x0f))
+	case c&0xe0 == 0xa0:
+		return decodeString(b, int(c&0x1f))
+	}
+	switch c {
+	case 0xc0:
+		return nil, b, nil
+	case 0xc2, 0xc3:
+		return c == 0xc3, b, nil
+	case 0xc4:
+		return decodeBytes(b[1:], int(b[0]))
+	case 0xcb:
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), b[8:], nil
+	case 0xcf:
+		return binary.BigEndian.Uint64(b), b[8:], nil
+	case 0xd0:
+		return int64(int8(b[0])), b[1:], nil
+	case 0xd1:
+		return int64(int16(binary.BigEndian.Uint16(b))), b[2:], nil
+	case 0xd2:
+		return int64(int32(binary.BigEndian.Uint32(b))), b[4:], nil
+	case 0xd3:
+		return int64(binary.BigEndian.Uint64(b)), b[8:], nil
+	case 0xd7:
+		if b[0] != 0xff {
+			return nil, nil, fmt.Errorf("unexpected extension type %d", int8(b[0]))
+		}
+		data := binary.BigEndian.Uint64(b[1:])
+		return time.Unix(int64(data&(1<<34-1)), int64(data>>34)), b[9:], nil
+	case 0xc7:
+		if b[0] != 12 || b[1] != 0xff {
+			return nil, nil, fmt.Errorf("unexpected extension type %d", int8(b[1]))
+		}
+		nsec, sec := binary.BigEndian.Uint32(b[2:]), binary.BigEndian.Uint64(b[6:])
+		return time.Unix(int64(sec), int64(nsec)), b[14:], nil
+	case 0xd9:
+		return decodeString(b[1:], int(b[0]))
+	case 0xda:
+		return decodeString(b[2:], int(binary.BigEndian.Uint16(b)))
+	case 0xdc:
+		return decodeArray(b[2:], int(binary.BigEndian.Uint16(b)))
+	case 0xde:
+		return decodeMap(b[2:], int(binary.BigEndian.Uint16(b)))
+	}
+	return nil, nil, fmt.Errorf("unsupported format 0x%x", c)
+}
+
+func decodeString(b []byte, n int) (interface{}, []byte, error) {
+	return string(b[:n]), b[n:], nil
+}
+
+func decodeBytes(b []byte, n int) (interface{}, []byte, error) {
+	return b[:n], b[n:], nil
+}
+
+func decodeArray(b []byte, n int) (interface{}, []byte, error) {
+	array := make([]interface{}, n)
+	for i := range array {
+		var err error
+		if array[i], b, err = decode(b); err != nil {
+			return nil, nil, err
+		}
+	}
+	return array, b, nil
+}
+
+func decodeMap(b []byte, n int) (interface{}, []byte, error) {
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, rest, err := decode(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("unexpected key %v", k)
+		}
+		if m[key], b, err = decode(rest); err != nil {
+			return nil, nil, err
+		}
+	}
+	return m, b, nil
+}
+
+// decodeRecords decodes the records written back to back in b.
+func decodeRecords(t *testing.T, b []byte) []map[string]interface{} {
+	var records []map[string]interface{}
+	for len(b) > 0 {
+		v, rest, err := decode(b)
+		require.NoError(t, err)
+		require.IsType(t, map[string]interface{}{}, v)
+		records = append(records, v.(map[string]interface{}))
+		b = rest
+	}
+	return records
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, nil)).With("service", "orders").WithGroup("app").With("version", "1.0")
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "test-request"})
+	now := time.Now()
+	logger.WarnContext(ctx, "order delayed", "count", 3, "ratio", 0.5, "ok", true, "at", now,
+		"timeout", time.Second, "err", errors.New("failed"), "tags", []string{"a", "b"})
+	logger.Info("second")
+
+	records := decodeRecords(t, buf.Bytes())
+	require.Len(t, records, 2)
+	record := records[0]
+	require.IsType(t, time.Time{}, record["timestamp"])
+	assert.WithinDuration(t, now, record["timestamp"].(time.Time), time.Minute)
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "order delayed", record["message"])
+	assert.Equal(t, "test-request", record["requestId"])
+	assert.Equal(t, "orders", record["service"])
+
+	app := record["app"].(map[string]interface{})
+	assert.True(t, now.Equal(app["at"].(time.Time)))
+	delete(app, "at")
+	assert.Equal(t, map[string]interface{}{
+		"version": "1.0",
+		"count":   int64(3),
+		"ratio":   0.5,
+		"ok":      true,
+		"timeout": "1s",
+		"err":     "failed",
+		"tags":    []interface{}{"a", "b"},
+	}, app)
+
+	assert.Equal(t, "second", records[1]["message"])
+	assert.NotContains(t, records[1], "requestId")
+}
+
+func TestHandler_ReservedFields(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewHandler(&buf, nil)).Info("reserved", "message", "replaced", "level", "replaced")
+
+	records := decodeRecords(t, buf.Bytes())
+	require.Len(t, records, 1)
+	assert.Equal(t, "reserved", records[0]["message"])
+	assert.Equal(t, "INFO", records[0]["level"])
+}
+
+func TestAppendValue(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	many := make([]interface{}, 20)
+	for i := range many {
+		many[i] = int64(i)
+	}
+	tests := []interface{}{
+		nil,
+		int64(0),
+		int64(-32),
+		int64(-33),
+		int64(200),
+		int64(-40000),
+		int64(1 << 40),
+		int64(math.MinInt64),
+		uint64(math.MaxUint64),
+		math.Pi,
+		"",
+		strings.Repeat("x", 31),
+		strings.Repeat("x", 32),
+		long,
+		[]byte("raw"),
+		many,
+		time.Unix(1<<35, 123).UTC(),
+		time.Unix(-1, 0).UTC(),
+	}
+	for _, v := range tests {
+		decoded, rest, err := decode(appendValue(nil, v))
+		require.NoError(t, err)
+		assert.Empty(t, rest)
+		if tm, ok := v.(time.Time); ok {
+			assert.True(t, tm.Equal(decoded.(time.Time)), "%v", v)
+			continue
+		}
+		assert.Equal(t, v, decoded)
+	}
+}